@@ -0,0 +1,82 @@
+package access
+
+import (
+	"fmt"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Maximal number of checks that can be sent in a single request.
+const MaxAccessChecks = 100
+
+// AccessCheck describes action user wants to perform on a resource, i.e. 'delete' of 'deployments'
+// in 'default' namespace.
+type AccessCheck struct {
+	Namespace   string `json:"namespace"`
+	Verb        string `json:"verb"`
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource"`
+	Name        string `json:"name"`
+}
+
+// AccessCheckSpec is a list of checks sent in a single request.
+type AccessCheckSpec struct {
+	Checks []AccessCheck `json:"checks"`
+}
+
+// AccessCheckResult tells whether user is allowed to perform the checked action.
+type AccessCheckResult struct {
+	AccessCheck `json:",inline"`
+	Allowed     bool   `json:"allowed"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// AccessCheckResultList contains results of checks in the order they have been sent.
+type AccessCheckResultList struct {
+	Results []AccessCheckResult `json:"results"`
+}
+
+// CanI checks whether user of the given client is allowed to perform listed actions. Every check is
+// reviewed by apiserver with SelfSubjectAccessReview. Action is not allowed if the review fails. Bad request
+// error is returned if there are too many checks.
+func CanI(client kubernetes.Interface, spec *AccessCheckSpec) (*AccessCheckResultList, error) {
+	if len(spec.Checks) > MaxAccessChecks {
+		return nil, k8sErrors.NewBadRequest(fmt.Sprintf("Too many access checks: %d. Maximal number of checks "+
+			"is %d.", len(spec.Checks), MaxAccessChecks))
+	}
+
+	result := &AccessCheckResultList{Results: make([]AccessCheckResult, 0, len(spec.Checks))}
+
+	for _, check := range spec.Checks {
+		review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(toSelfSubjectAccessReview(check))
+		if err != nil {
+			result.Results = append(result.Results, AccessCheckResult{AccessCheck: check, Reason: err.Error()})
+			continue
+		}
+
+		result.Results = append(result.Results, AccessCheckResult{
+			AccessCheck: check,
+			Allowed:     review.Status.Allowed,
+			Reason:      review.Status.Reason,
+		})
+	}
+
+	return result, nil
+}
+
+func toSelfSubjectAccessReview(check AccessCheck) *authorizationV1.SelfSubjectAccessReview {
+	return &authorizationV1.SelfSubjectAccessReview{
+		Spec: authorizationV1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationV1.ResourceAttributes{
+				Namespace:   check.Namespace,
+				Verb:        check.Verb,
+				Group:       check.Group,
+				Resource:    check.Resource,
+				Subresource: check.Subresource,
+				Name:        check.Name,
+			},
+		},
+	}
+}
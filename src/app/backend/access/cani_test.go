@@ -0,0 +1,14 @@
+package access
+
+import (
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestCanI_TooManyChecks(t *testing.T) {
+	spec := &AccessCheckSpec{Checks: make([]AccessCheck, MaxAccessChecks+1)}
+	if _, err := CanI(fake.NewSimpleClientset(), spec); !k8sErrors.IsBadRequest(err) {
+		t.Errorf("CanI(): Expected bad request for too many checks but got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package access
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clientcmdApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Authentication methods the user identity can be determined from.
+const (
+	AuthMethodToken         = "token"
+	AuthMethodBasic         = "basic"
+	AuthMethodCertificate   = "certificate"
+	AuthMethodImpersonation = "impersonation"
+	AuthMethodNone          = "none"
+)
+
+// Identity describes user that sends requests to apiserver.
+type Identity struct {
+	// Username of the user. Empty if it can not be determined.
+	Username string `json:"username"`
+	// UID of the user. It is set only if the identity has been read from TokenReview.
+	UID string `json:"uid,omitempty"`
+	// Groups the user belongs to.
+	Groups []string `json:"groups"`
+	// AuthMethod is the way user authenticates to apiserver, i.e. 'token'.
+	AuthMethod string `json:"authMethod"`
+}
+
+// NamespacePermissions is a summary of actions user can perform within a namespace.
+type NamespacePermissions struct {
+	Namespace string `json:"namespace"`
+	// ResourceRules is the list of actions user can perform on resources.
+	ResourceRules []authorizationV1.ResourceRule `json:"resourceRules"`
+	// NonResourceRules is the list of actions user can perform on non-resources.
+	NonResourceRules []authorizationV1.NonResourceRule `json:"nonResourceRules"`
+	// Incomplete is true when the rules returned by apiserver are incomplete, i.e. the authorizer does
+	// not support rules evaluation.
+	Incomplete bool `json:"incomplete"`
+	// EvaluationError contains errors that occurred during rules evaluation.
+	EvaluationError string `json:"evaluationError,omitempty"`
+}
+
+// Me contains identity of the current user and actions the user can perform in the cluster.
+type Me struct {
+	Identity    Identity               `json:"identity"`
+	Permissions []NamespacePermissions `json:"permissions"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetMe returns identity of the user described by given auth info and user permissions in namespaces
+// matching the query. Client has to be created with the same auth info, while authClient is used to
+// review tokens and requires permission to create TokenReviews.
+func GetMe(client, authClient kubernetes.Interface, authInfo *clientcmdApi.AuthInfo,
+	nsQuery *common.NamespaceQuery) (*Me, error) {
+	me := &Me{Permissions: make([]NamespacePermissions, 0), Errors: make([]error, 0)}
+
+	identity, err := GetIdentity(authClient, authInfo)
+	me.Errors, err = kcErrors.AppendError(err, me.Errors)
+	if err != nil {
+		return nil, err
+	}
+	me.Identity = identity
+
+	namespaces, err := getNamespaces(client, nsQuery)
+	me.Errors, err = kcErrors.AppendError(err, me.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range namespaces {
+		permissions, err := getNamespacePermissions(client, namespace)
+		me.Errors, err = kcErrors.AppendError(err, me.Errors)
+		if err != nil {
+			return nil, err
+		}
+
+		if permissions != nil {
+			me.Permissions = append(me.Permissions, *permissions)
+		}
+	}
+
+	return me, nil
+}
+
+// GetIdentity returns identity of the user described by given auth info. Bearer tokens are reviewed
+// by apiserver using given client.
+func GetIdentity(authClient kubernetes.Interface, authInfo *clientcmdApi.AuthInfo) (Identity, error) {
+	identity := Identity{Groups: make([]string, 0), AuthMethod: AuthMethodNone}
+	if authInfo == nil {
+		return identity, nil
+	}
+
+	switch {
+	case len(authInfo.Impersonate) > 0:
+		identity.AuthMethod = AuthMethodImpersonation
+		identity.Username = authInfo.Impersonate
+		identity.Groups = append(identity.Groups, authInfo.ImpersonateGroups...)
+	case len(authInfo.Token) > 0:
+		identity.AuthMethod = AuthMethodToken
+		return reviewToken(authClient, authInfo.Token, identity)
+	case len(authInfo.ClientCertificateData) > 0:
+		identity.AuthMethod = AuthMethodCertificate
+		return readCertificate(authInfo.ClientCertificateData, identity)
+	case len(authInfo.Username) > 0:
+		identity.AuthMethod = AuthMethodBasic
+		identity.Username = authInfo.Username
+	}
+
+	return identity, nil
+}
+
+func reviewToken(authClient kubernetes.Interface, token string, identity Identity) (Identity, error) {
+	review, err := authClient.AuthenticationV1().TokenReviews().Create(&authenticationV1.TokenReview{
+		Spec: authenticationV1.TokenReviewSpec{Token: token},
+	})
+	if err != nil {
+		return identity, err
+	}
+
+	if !review.Status.Authenticated {
+		if len(review.Status.Error) > 0 {
+			return identity, errors.New(review.Status.Error)
+		}
+		return identity, errors.New("Token has not been authenticated by apiserver.")
+	}
+
+	identity.Username = review.Status.User.Username
+	identity.UID = review.Status.User.UID
+	identity.Groups = append(identity.Groups, review.Status.User.Groups...)
+	return identity, nil
+}
+
+// Apiserver uses common name of client certificate as username and organizations as groups.
+func readCertificate(certData []byte, identity Identity) (Identity, error) {
+	block, _ := pem.Decode(certData)
+	if block == nil {
+		return identity, errors.New("Could not decode client certificate.")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return identity, err
+	}
+
+	identity.Username = cert.Subject.CommonName
+	identity.Groups = append(identity.Groups, cert.Subject.Organization...)
+	return identity, nil
+}
+
+// Returns namespaces matching the query. All namespaces are listed only if the query does not
+// select a single namespace.
+func getNamespaces(client kubernetes.Interface, nsQuery *common.NamespaceQuery) ([]string, error) {
+	if namespace := nsQuery.ToRequestParam(); namespace != metaV1.NamespaceAll {
+		return []string{namespace}, nil
+	}
+
+	list, err := client.CoreV1().Namespaces().List(api.ListEverything)
+	if err != nil {
+		return []string{}, err
+	}
+
+	namespaces := make([]string, 0)
+	for _, namespace := range list.Items {
+		if nsQuery.Matches(namespace.Name) {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	return namespaces, nil
+}
+
+func getNamespacePermissions(client kubernetes.Interface, namespace string) (*NamespacePermissions, error) {
+	review, err := client.AuthorizationV1().SelfSubjectRulesReviews().Create(&authorizationV1.SelfSubjectRulesReview{
+		Spec: authorizationV1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &NamespacePermissions{
+		Namespace:        namespace,
+		ResourceRules:    review.Status.ResourceRules,
+		NonResourceRules: review.Status.NonResourceRules,
+		Incomplete:       review.Status.Incomplete,
+		EvaluationError:  review.Status.EvaluationError,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package access
+
+import (
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	clientcmdApi "k8s.io/client-go/tools/clientcmd/api"
+	"reflect"
+	"testing"
+)
+
+func TestGetMe(t *testing.T) {
+	authClient := fake.NewSimpleClientset()
+	authClient.PrependReactor("create", "tokenreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authenticationV1.TokenReview)
+		review.Status = authenticationV1.TokenReviewStatus{
+			Authenticated: review.Spec.Token == "test-token",
+			User:          authenticationV1.UserInfo{Username: "alice", Groups: []string{"dev"}},
+		}
+		return true, review, nil
+	})
+
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectrulesreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authorizationV1.SelfSubjectRulesReview)
+		review.Status = authorizationV1.SubjectRulesReviewStatus{
+			ResourceRules: []authorizationV1.ResourceRule{{Verbs: []string{"get"}, Resources: []string{"pods"}}},
+		}
+		return true, review, nil
+	})
+
+	me, err := GetMe(client, authClient, &clientcmdApi.AuthInfo{Token: "test-token"},
+		common.NewOneNamespaceQuery("default"))
+	if err != nil {
+		t.Fatalf("GetMe(): Unexpected error: %s", err)
+	}
+
+	expected := Identity{Username: "alice", Groups: []string{"dev"}, AuthMethod: AuthMethodToken}
+	if !reflect.DeepEqual(me.Identity, expected) {
+		t.Errorf("GetMe(): Expected identity %#v but got %#v", expected, me.Identity)
+	}
+
+	if len(me.Permissions) != 1 || me.Permissions[0].Namespace != "default" ||
+		len(me.Permissions[0].ResourceRules) != 1 {
+		t.Errorf("GetMe(): Expected permissions in default namespace but got %#v", me.Permissions)
+	}
+}
+
+func TestGetIdentity(t *testing.T) {
+	cases := []struct {
+		authInfo *clientcmdApi.AuthInfo
+		expected Identity
+	}{
+		{
+			nil,
+			Identity{Groups: []string{}, AuthMethod: AuthMethodNone},
+		},
+		{
+			&clientcmdApi.AuthInfo{Impersonate: "bob", ImpersonateGroups: []string{"ops"}},
+			Identity{Username: "bob", Groups: []string{"ops"}, AuthMethod: AuthMethodImpersonation},
+		},
+		{
+			&clientcmdApi.AuthInfo{Username: "admin", Password: "secret"},
+			Identity{Username: "admin", Groups: []string{}, AuthMethod: AuthMethodBasic},
+		},
+	}
+
+	for _, c := range cases {
+		identity, err := GetIdentity(fake.NewSimpleClientset(), c.authInfo)
+		if err != nil {
+			t.Fatalf("GetIdentity(%#v): Unexpected error: %s", c.authInfo, err)
+		}
+
+		if !reflect.DeepEqual(identity, c.expected) {
+			t.Errorf("GetIdentity(%#v): Expected %#v but got %#v", c.authInfo, c.expected, identity)
+		}
+	}
+}
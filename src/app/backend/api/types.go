@@ -6,6 +6,14 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+// CsrfTokenHeader is the name of request header that contains csrf token. It is required by all
+// requests that can modify resources (POST, PUT, PATCH and DELETE).
+const CsrfTokenHeader = "X-CSRF-TOKEN"
+
+// CacheStatusHeader is the name of response header that contains status of resource cache, i.e. 'stale'
+// if lists served from memory may be outdated. The header is set only if the cache is enabled.
+const CacheStatusHeader = "X-K8SCONSOLE-CACHE-STATUS"
+
 // CsrfToken is used to secure requests from CSRF attacks
 type CsrfToken struct {
 	// Token generated on request for validation
@@ -42,6 +50,11 @@ type TypeMeta struct {
 // ListMeta describes list of objects.
 type ListMeta struct {
 	TotalItems int `json:"totalItems"`
+
+	// Continue is the token of the next page of lists paginated by apiserver. It is empty on the last page and
+	// for lists paginated in memory. TotalItems is the number of items on the page for paginated lists, as
+	// apiserver does not count remaining items.
+	Continue string `json:"continue,omitempty"`
 }
 
 // NewObjectMeta creates a new instance of ObjectMate struct based on k8s object meta.
@@ -94,55 +107,6 @@ const (
 	ResourceKindEndpoint								 = "endpoint"
 )
 
-// ClientType represents type of client that is used to perform generic operations on resources.
-// Different resources belong to different client, i.e. Deployments belongs to extension client
-// and StatefulSets to apps client.
-type ClientType string
-
-// List of client types.
-const (
-	ClientTypeDefault         		= "restclient"
-	ClientTypeExtensionClient   	= "extensionclient"
-	ClientTypeAppsClient        	= "appsclient"
-	ClientTypeBatchClient       	= "batchclient"
-	ClientTypeBetaBatchClient   	= "betabatchclient"
-	ClientTypeAutoscalingClient 	= "autoscalingclient"
-	ClientTypeStorageClient     	= "storageclient"
-)
-
-// Mapping from resource kind to K8s apiserver API path.
-var KindToAPIMapping = map[string]struct {
-	// k8s resource name
-	Resource string
-	// Client type used by given resource, i.e. deployments using extension client
-	ClientType ClientType
-	// Is this object global scoped (not below a namespace), i.e. 'kubectl get node'
-	Namespaced bool
-}{
-	ResourceKindConfigMap:               {"configmaps", ClientTypeDefault, true},
-	ResourceKindDaemonSet:               {"daemonsets", ClientTypeExtensionClient, true},
-	ResourceKindDeployment:              {"deployments", ClientTypeExtensionClient, true},
-	ResourceKindEvent:                   {"events", ClientTypeDefault, true},
-	ResourceKindHorizontalPodAutoscaler: {"horizontalpodautoscalers", ClientTypeAutoscalingClient, true},
-	ResourceKindIngress:                 {"ingresses", ClientTypeExtensionClient, true},
-	ResourceKindJob:                     {"jobs", ClientTypeBatchClient, true},
-	ResourceKindCronJob:                 {"cronjobs", ClientTypeBetaBatchClient, true},
-	ResourceKindLimitRange:              {"limitrange", ClientTypeDefault, true},
-	ResourceKindNamespace:               {"namespaces", ClientTypeDefault, false},
-	ResourceKindNode:                    {"nodes", ClientTypeDefault, false},
-	ResourceKindPersistentVolumeClaim:   {"persistentvolumeclaims", ClientTypeDefault, true},
-	ResourceKindPersistentVolume:        {"persistentvolumes", ClientTypeDefault, false},
-	ResourceKindPod:                     {"pods", ClientTypeDefault, true},
-	ResourceKindReplicaSet:              {"replicasets", ClientTypeExtensionClient, true},
-	ResourceKindReplicationController:   {"replicationcontrollers", ClientTypeDefault, true},
-	ResourceKindResourceQuota:           {"resourcequotas", ClientTypeDefault, true},
-	ResourceKindSecret:                  {"secrets", ClientTypeDefault, true},
-	ResourceKindService:                 {"services", ClientTypeDefault, true},
-	ResourceKindStatefulSet:             {"statefulsets", ClientTypeAppsClient, true},
-	ResourceKindStorageClass:            {"storageclasses", ClientTypeStorageClient, false},
-	ResourceKindEndpoint:                {"endpoints", ClientTypeDefault, true},
-}
-
 // IsSelectorMatching returns true when an object with the given selector targets the same
 // resource that the tested object with the given selector. - means labelSelector is the subset of testedObjectLabels
 func IsSelectorMatching(selector map[string]string, testedObjectLabels map[string]string) bool {
@@ -175,3 +139,18 @@ var ListEverything = metaV1.ListOptions{
 	LabelSelector: labels.Everything().String(),
 	FieldSelector: fields.Everything().String(),
 }
+
+// Metadata fields populated by apiserver. They are not part of the object as specified by the user.
+var serverPopulatedMetadataFields = []string{"uid", "resourceVersion", "generation", "selfLink",
+	"creationTimestamp", "managedFields"}
+
+// RemoveServerPopulatedFields removes status and metadata fields populated by apiserver from decoded object,
+// leaving only the fields specified by the user.
+func RemoveServerPopulatedFields(object map[string]interface{}) {
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverPopulatedMetadataFields {
+			delete(metadata, field)
+		}
+	}
+}
@@ -34,6 +34,24 @@ func (self *holderBuilder) SetBindAddress(ip net.IP) *holderBuilder {
 	return self
 }
 
+// SetTLSCertFile 'tls-cert-file' argument of k8sconsole.
+func (self *holderBuilder) SetTLSCertFile(tlsCertFile string) *holderBuilder {
+	self.holder.tlsCertFile = tlsCertFile
+	return self
+}
+
+// SetTLSKeyFile 'tls-key-file' argument of k8sconsole.
+func (self *holderBuilder) SetTLSKeyFile(tlsKeyFile string) *holderBuilder {
+	self.holder.tlsKeyFile = tlsKeyFile
+	return self
+}
+
+// SetAutoGenerateCertificates 'auto-generate-certificates' argument of k8sconsole.
+func (self *holderBuilder) SetAutoGenerateCertificates(autoGenerateCertificates bool) *holderBuilder {
+	self.holder.autoGenerateCertificates = autoGenerateCertificates
+	return self
+}
+
 // SetApiServerHost 'apiserver-host' argument of k8sconsole.
 func (self *holderBuilder) SetApiServerHost(apiServerHost string) *holderBuilder {
 	self.holder.apiServerHost = apiServerHost
@@ -46,12 +64,36 @@ func (self *holderBuilder) SetKubeConfigFile(kubeConfigFile string) *holderBuild
 	return self
 }
 
+// SetClustersKubeConfigPath 'clusters-kubeconfig' argument of k8sconsole.
+func (self *holderBuilder) SetClustersKubeConfigPath(path string) *holderBuilder {
+	self.holder.clustersKubeConfigPath = path
+	return self
+}
+
 // SetTokenTTL 'token-ttl' argument of k8sconsole.
 func (self *holderBuilder) SetTokenTTL(ttl int) *holderBuilder {
 	self.holder.tokenTTL = ttl
 	return self
 }
 
+// SetTokenKeyRotationPeriod 'token-key-rotation-period' argument of k8sconsole.
+func (self *holderBuilder) SetTokenKeyRotationPeriod(period int) *holderBuilder {
+	self.holder.tokenKeyRotationPeriod = period
+	return self
+}
+
+// SetTokenKeyRingSize 'token-key-ring-size' argument of k8sconsole.
+func (self *holderBuilder) SetTokenKeyRingSize(size int) *holderBuilder {
+	self.holder.tokenKeyRingSize = size
+	return self
+}
+
+// SetShareTokenRevocations 'share-token-revocations' argument of k8sconsole.
+func (self *holderBuilder) SetShareTokenRevocations(share bool) *holderBuilder {
+	self.holder.shareTokenRevocations = share
+	return self
+}
+
 // SetAuthenticationMode 'authentication-mode' argument of k8sconsole.
 func (self *holderBuilder) SetAuthenticationMode(authMode []string) *holderBuilder {
 	self.holder.authenticationMode = authMode
@@ -64,12 +106,84 @@ func (self *holderBuilder) SetDisableSkipButton(disableSkipButton bool) *holderB
 	return self
 }
 
+// SetOIDCIssuerURL 'oidc-issuer-url' argument of k8sconsole.
+func (self *holderBuilder) SetOIDCIssuerURL(issuerURL string) *holderBuilder {
+	self.holder.oidcIssuerURL = issuerURL
+	return self
+}
+
+// SetOIDCClientID 'oidc-client-id' argument of k8sconsole.
+func (self *holderBuilder) SetOIDCClientID(clientID string) *holderBuilder {
+	self.holder.oidcClientID = clientID
+	return self
+}
+
+// SetOIDCClientSecret 'oidc-client-secret' argument of k8sconsole.
+func (self *holderBuilder) SetOIDCClientSecret(clientSecret string) *holderBuilder {
+	self.holder.oidcClientSecret = clientSecret
+	return self
+}
+
+// SetOIDCRedirectURL 'oidc-redirect-url' argument of k8sconsole.
+func (self *holderBuilder) SetOIDCRedirectURL(redirectURL string) *holderBuilder {
+	self.holder.oidcRedirectURL = redirectURL
+	return self
+}
+
+// SetOIDCScopes 'oidc-scopes' argument of k8sconsole.
+func (self *holderBuilder) SetOIDCScopes(scopes []string) *holderBuilder {
+	self.holder.oidcScopes = scopes
+	return self
+}
+
+// SetAuthProxyUserHeader 'authentication-proxy-user-header' argument of k8sconsole.
+func (self *holderBuilder) SetAuthProxyUserHeader(userHeader string) *holderBuilder {
+	self.holder.authProxyUserHeader = userHeader
+	return self
+}
+
+// SetAuthProxyGroupHeader 'authentication-proxy-group-header' argument of k8sconsole.
+func (self *holderBuilder) SetAuthProxyGroupHeader(groupHeader string) *holderBuilder {
+	self.holder.authProxyGroupHeader = groupHeader
+	return self
+}
+
+// SetAuthProxyTrustedCIDRs 'authentication-proxy-trusted-cidrs' argument of k8sconsole.
+func (self *holderBuilder) SetAuthProxyTrustedCIDRs(trustedCIDRs []string) *holderBuilder {
+	self.holder.authProxyTrustedCIDRs = trustedCIDRs
+	return self
+}
+
+// SetAuthProxyClientCAFile 'authentication-proxy-client-ca-file' argument of k8sconsole.
+func (self *holderBuilder) SetAuthProxyClientCAFile(clientCAFile string) *holderBuilder {
+	self.holder.authProxyClientCAFile = clientCAFile
+	return self
+}
+
 // SetEnableInsecureLogin 'enable-insecure-login' argument of k8sconsole.
 func (self *holderBuilder) SetEnableInsecureLogin(enableInsecureLogin bool) *holderBuilder {
 	self.holder.enableInsecureLogin = enableInsecureLogin
 	return self
 }
 
+// SetEnableResourceCache 'enable-resource-cache' argument of k8sconsole.
+func (self *holderBuilder) SetEnableResourceCache(enableResourceCache bool) *holderBuilder {
+	self.holder.enableResourceCache = enableResourceCache
+	return self
+}
+
+// SetHistoryBackend 'history-backend' argument of k8sconsole.
+func (self *holderBuilder) SetHistoryBackend(historyBackend string) *holderBuilder {
+	self.holder.historyBackend = historyBackend
+	return self
+}
+
+// SetHistorySize 'history-size' argument of k8sconsole.
+func (self *holderBuilder) SetHistorySize(historySize int) *holderBuilder {
+	self.holder.historySize = historySize
+	return self
+}
+
 // GetHolderBuilder returns singletone instance of argument holder builder.
 func GetHolderBuilder() *holderBuilder {
 	return builder
@@ -12,14 +12,37 @@ type holder struct {
 	insecureBindAddress net.IP
 	bindAddress         net.IP
 
-	apiServerHost  string
-	kubeConfigFile string
-
-	tokenTTL           int
-	authenticationMode []string
-	disableSkipButton  bool
+	tlsCertFile              string
+	tlsKeyFile               string
+	autoGenerateCertificates bool
+
+	apiServerHost          string
+	kubeConfigFile         string
+	clustersKubeConfigPath string
+
+	tokenTTL               int
+	tokenKeyRotationPeriod int
+	tokenKeyRingSize       int
+	shareTokenRevocations  bool
+	authenticationMode     []string
+	disableSkipButton      bool
+
+	oidcIssuerURL    string
+	oidcClientID     string
+	oidcClientSecret string
+	oidcRedirectURL  string
+	oidcScopes       []string
+
+	authProxyUserHeader   string
+	authProxyGroupHeader  string
+	authProxyTrustedCIDRs []string
+	authProxyClientCAFile string
 
 	enableInsecureLogin bool
+	enableResourceCache bool
+
+	historyBackend string
+	historySize    int
 }
 
 // GetInsecurePort 'insecure-port' argument of k8sconsole.
@@ -42,6 +65,21 @@ func (self *holder) GetBindAddress() net.IP {
 	return self.bindAddress
 }
 
+// GetTLSCertFile 'tls-cert-file' argument of k8sconsole.
+func (self *holder) GetTLSCertFile() string {
+	return self.tlsCertFile
+}
+
+// GetTLSKeyFile 'tls-key-file' argument of k8sconsole.
+func (self *holder) GetTLSKeyFile() string {
+	return self.tlsKeyFile
+}
+
+// GetAutoGenerateCertificates 'auto-generate-certificates' argument of k8sconsole.
+func (self *holder) GetAutoGenerateCertificates() bool {
+	return self.autoGenerateCertificates
+}
+
 // GetApiServerHost 'apiserver-host' argument of k8sconsole.
 func (self *holder) GetApiServerHost() string {
 	return self.apiServerHost
@@ -52,11 +90,31 @@ func (self *holder) GetKubeConfigFile() string {
 	return self.kubeConfigFile
 }
 
+// GetClustersKubeConfigPath 'clusters-kubeconfig' argument of k8sconsole.
+func (self *holder) GetClustersKubeConfigPath() string {
+	return self.clustersKubeConfigPath
+}
+
 // GetTokenTTL 'token-ttl' argument of k8sconsole.
 func (self *holder) GetTokenTTL() int {
 	return self.tokenTTL
 }
 
+// GetTokenKeyRotationPeriod 'token-key-rotation-period' argument of k8sconsole.
+func (self *holder) GetTokenKeyRotationPeriod() int {
+	return self.tokenKeyRotationPeriod
+}
+
+// GetTokenKeyRingSize 'token-key-ring-size' argument of k8sconsole.
+func (self *holder) GetTokenKeyRingSize() int {
+	return self.tokenKeyRingSize
+}
+
+// GetShareTokenRevocations 'share-token-revocations' argument of k8sconsole.
+func (self *holder) GetShareTokenRevocations() bool {
+	return self.shareTokenRevocations
+}
+
 // GetAuthenticationMode 'authentication-mode' argument of k8sconsole.
 func (self *holder) GetAuthenticationMode() []string {
 	return self.authenticationMode
@@ -67,7 +125,67 @@ func (self *holder) GetDisableSkipButton() bool {
 	return self.disableSkipButton
 }
 
+// GetOIDCIssuerURL 'oidc-issuer-url' argument of k8sconsole.
+func (self *holder) GetOIDCIssuerURL() string {
+	return self.oidcIssuerURL
+}
+
+// GetOIDCClientID 'oidc-client-id' argument of k8sconsole.
+func (self *holder) GetOIDCClientID() string {
+	return self.oidcClientID
+}
+
+// GetOIDCClientSecret 'oidc-client-secret' argument of k8sconsole.
+func (self *holder) GetOIDCClientSecret() string {
+	return self.oidcClientSecret
+}
+
+// GetOIDCRedirectURL 'oidc-redirect-url' argument of k8sconsole.
+func (self *holder) GetOIDCRedirectURL() string {
+	return self.oidcRedirectURL
+}
+
+// GetOIDCScopes 'oidc-scopes' argument of k8sconsole.
+func (self *holder) GetOIDCScopes() []string {
+	return self.oidcScopes
+}
+
+// GetAuthProxyUserHeader 'authentication-proxy-user-header' argument of k8sconsole.
+func (self *holder) GetAuthProxyUserHeader() string {
+	return self.authProxyUserHeader
+}
+
+// GetAuthProxyGroupHeader 'authentication-proxy-group-header' argument of k8sconsole.
+func (self *holder) GetAuthProxyGroupHeader() string {
+	return self.authProxyGroupHeader
+}
+
+// GetAuthProxyTrustedCIDRs 'authentication-proxy-trusted-cidrs' argument of k8sconsole.
+func (self *holder) GetAuthProxyTrustedCIDRs() []string {
+	return self.authProxyTrustedCIDRs
+}
+
+// GetAuthProxyClientCAFile 'authentication-proxy-client-ca-file' argument of k8sconsole.
+func (self *holder) GetAuthProxyClientCAFile() string {
+	return self.authProxyClientCAFile
+}
+
 // GetEnableInsecureLogin 'enable-insecure-login' argument of k8sconsole.
 func (self *holder) GetEnableInsecureLogin() bool {
 	return self.enableInsecureLogin
 }
+
+// GetEnableResourceCache 'enable-resource-cache' argument of k8sconsole.
+func (self *holder) GetEnableResourceCache() bool {
+	return self.enableResourceCache
+}
+
+// GetHistoryBackend 'history-backend' argument of k8sconsole.
+func (self *holder) GetHistoryBackend() string {
+	return self.historyBackend
+}
+
+// GetHistorySize 'history-size' argument of k8sconsole.
+func (self *holder) GetHistorySize() int {
+	return self.historySize
+}
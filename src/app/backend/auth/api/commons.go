@@ -1,13 +1,16 @@
 package api
 
-import "strings"
+import (
+	certApi "github.com/wzt3309/k8sconsole/src/app/backend/cert/api"
+	"strings"
+)
 
 // ToAuthenticationModes convert array of authentication mode strings to valid Authentication type.
 func ToAuthenticationModes(modes []string) AuthenticationModes {
 	res := AuthenticationModes{}
 	modesMap := map[string]bool{}
 
-	for _, mode := range []AuthenticationMode{Basic, Token} {
+	for _, mode := range []AuthenticationMode{Basic, Token, KubeConfig, OIDC} {
 		modesMap[mode.String()] = true
 	}
 
@@ -20,7 +23,37 @@ func ToAuthenticationModes(modes []string) AuthenticationModes {
 	return res
 }
 
+// Objects used by k8sconsole that should never be accessed through its API. Empty namespace means that
+// objects with the name are restricted in all namespaces.
+var restrictedObjects = []struct {
+	namespace string
+	name      string
+}{
+	{EncryptionKeyHolderNamespace, EncrytionKeyHolderName},
+	{EncryptionKeyHolderNamespace, TokenRevocationListName},
+	{certApi.CertHolderNamespace, certApi.CertHolderName},
+	{"", HistoryHolderName},
+}
+
 // ShouldRejectRequest returns true if url contains name and namespace of resource that should be filtered out
 func ShouldRejectRquest(url string) bool {
-	return strings.Contains(url, EncrytionKeyHolderName) && strings.Contains(url, EncryptionKeyHolderNamespace)
+	for _, object := range restrictedObjects {
+		if strings.Contains(url, object.name) && strings.Contains(url, object.namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShouldRejectObject returns true if object with given namespace and name should be filtered out. It is used
+// when objects are not selected by url, i.e. by bulk actions.
+func ShouldRejectObject(namespace, name string) bool {
+	for _, object := range restrictedObjects {
+		if (len(object.namespace) == 0 || namespace == object.namespace) && name == object.name {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,31 @@
+package api
+
+import "testing"
+
+func TestShouldRejectRquest(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected bool
+	}{
+		{"/api/v1/secret/kube-system/k8sconsole-key-holder", true},
+		{"/api/v1/_raw/secret/namespace/kube-system/name/k8sconsole-token-revocations", true},
+		{"/api/v1/secret/kube-system/k8sconsole-certs", true},
+		{"/api/v1/secret/default/k8sconsole-certs", false},
+		{"/api/v1/secret/kube-system/default-token", false},
+		{"/api/v1/_raw/configmap/namespace/default/name/k8sconsole-history", true},
+	}
+
+	for _, c := range cases {
+		if actual := ShouldRejectRquest(c.url); actual != c.expected {
+			t.Errorf("ShouldRejectRquest(%s): Expected %t but got %t", c.url, c.expected, actual)
+		}
+	}
+
+	if !ShouldRejectObject("kube-system", "k8sconsole-certs") || ShouldRejectObject("default", "k8sconsole-certs") {
+		t.Error("ShouldRejectObject(): Expected only certificate secret from kube-system to be rejected")
+	}
+
+	if !ShouldRejectObject("default", "k8sconsole-history") {
+		t.Error("ShouldRejectObject(): Expected history to be rejected in all namespaces")
+	}
+}
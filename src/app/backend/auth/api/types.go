@@ -9,9 +9,19 @@ const (
 	// The name of key for encryption storage.
 	EncrytionKeyHolderName       = "k8sconsole-key-holder"
 	EncryptionKeyHolderNamespace = "kube-system"
+	// The name of secret that holds IDs of revoked tokens. It is stored in the same namespace as encryption key.
+	TokenRevocationListName = "k8sconsole-token-revocations"
+	// The name of ConfigMap or Secret that holds history of changes of objects of its namespace. It is stored
+	// in every namespace objects have been changed in.
+	HistoryHolderName = "k8sconsole-history"
 
 	// Expiration time (in sec) of tokens generated by k8sconsole. Default: 15 min.
 	DefaultTokenTTL = 900
+
+	// Keys of auth provider config entries that hold OIDC data embedded in the token. Expiry is the expiration
+	// time of ID token in RFC3339 format.
+	OIDCRefreshTokenKey = "refresh-token"
+	OIDCExpiryKey       = "expiry"
 )
 
 // AuthenticationModes represents which auth mode supported by k8sconsole.
@@ -53,8 +63,10 @@ func (self AuthenticationMode) String() string {
 
 // Authentication modes supported by k8sconsole should be defined below.
 const (
-	Basic AuthenticationMode = "basic"
-	Token AuthenticationMode = "token"
+	Basic      AuthenticationMode = "basic"
+	Token      AuthenticationMode = "token"
+	KubeConfig AuthenticationMode = "kubeconfig"
+	OIDC       AuthenticationMode = "oidc"
 )
 
 // AuthManager is used for user authentication management.
@@ -62,12 +74,18 @@ type AuthManager interface {
 	// Login authenticates user based on provided LoginSpec and returns AuthResponse.
 	Login(*LoginSpec) (*AuthResponse, error)
 	// Refresh takes valid token that hasn't expired yet and returns a new one with expiration time set to TokenTTL.
-	// In case provided token has expired, token expiration error is returned.
+	// Provided token is revoked. In case provided token has expired, token expiration error is returned.
 	Refresh(string) (string, error)
 	// AuthenticationModes returns array of auth modes supported by k8sconsole.
 	AuthenticationModes() []AuthenticationMode
 	//  AuthenticationSkippable tells if the Skip button should be enabled or not
 	AuthenticationSkippable() bool
+	// OIDCAuthURL returns URL of OpenID Connect issuer user should be redirected to in order to log in. State
+	// passed to the issuer is valid only together with given nonce, which has to be bound to the browser of
+	// the user, so authorization code obtained by other user can not be used to log in.
+	OIDCAuthURL(nonce string) (string, error)
+	// Logout revokes provided token, so it can not be used anymore even if it hasn't expired yet.
+	Logout(string) error
 }
 
 // TokenManager is used for generated and decrypting tokens used for authorization.
@@ -76,19 +94,34 @@ type AuthManager interface {
 type TokenManager interface {
 	// Generate secure token based on AuthInfo.
 	Generate(api.AuthInfo) (string, error)
+	// GenerateWithClusters generates secure token based on AuthInfo of the default cluster and AuthInfos
+	// of additional clusters mapped by cluster names.
+	GenerateWithClusters(api.AuthInfo, map[string]api.AuthInfo) (string, error)
 	// Decrypt generated token and extract AuthInfo from it which is used for creating k8s apiserver client
 	Decrypt(string) (*api.AuthInfo, error)
-	// Refresh returns refreshed token based on provided token. In case provided token has expired, token expiration
-	// error is returned.
+	// DecryptWithClusters decrypts generated token and extracts AuthInfo of the default cluster and AuthInfos
+	// of additional clusters mapped by cluster names.
+	DecryptWithClusters(string) (*api.AuthInfo, map[string]api.AuthInfo, error)
+	// Refresh returns refreshed token based on provided token and revokes provided token after a short grace
+	// period, so it can not outlive logout. In case provided token has expired, token expiration error is
+	// returned.
 	Refresh(string) (string, error)
+	// Revoke invalidates provided token before it expires.
+	Revoke(string) error
+	// RevokeRefreshed invalidates provided token replaced by a refreshed one after a short grace period, so
+	// concurrent requests sent with it are not rejected. Tokens that never expire are not revoked.
+	RevokeRefreshed(string) error
 	// SetTokenTTL sets expiration time (in sec) of generated tokens.
 	SetTokenTTL(time.Duration)
+	// TokenTTL returns expiration time of generated tokens. Zero is returned if tokens never expire.
+	TokenTTL() time.Duration
 }
 
 // Authenticator represents authentication methods, Currently supported types are:
 //	- Basic 			- Username and password based authentication
 //	- Token 			- Any bearer token accepted by apiserver
 //	- kubeConfig 	- Authenticates user based on kubeconfig file.
+//	- OIDC 				- Authenticates user with OpenID Connect authorization code flow.
 type Authenticator interface {
 	GetAuthInfo() (api.AuthInfo, error)
 }
@@ -105,6 +138,21 @@ type LoginSpec struct {
 	// KubeConfig is the content of users' kubeconfig file. We can extract all auth information
 	// from the data in the file.
 	KubeConfig string `json:"kubeConfig"`
+	// Context is the name of kubeconfig context used for 'kubeconfig' mode authentication. If it is empty
+	// then current context of the kubeconfig file is used.
+	Context string `json:"context"`
+	// OIDCCode is the authorization code returned by OpenID Connect issuer for 'oidc' mode authentication.
+	OIDCCode string `json:"oidcCode"`
+	// OIDCState is the state returned by OpenID Connect issuer together with authorization code.
+	OIDCState string `json:"oidcState"`
+	// OIDCNonce is the nonce the state has been generated for. It is read from the cookie set when user has
+	// been redirected to the issuer, never from the request body.
+	OIDCNonce string `json:"-"`
+	// Cluster is the name of registered cluster user logs in to. Empty name means the default cluster.
+	Cluster string `json:"cluster"`
+	// JWEToken is an optional token generated by previous login. Auth infos stored in it are kept in the new
+	// token, so user can be logged in to multiple clusters at once.
+	JWEToken string `json:"jweToken"`
 }
 
 // TokenRefreshSpec contains token that is required by token refresh operation.
@@ -113,6 +161,12 @@ type TokenRefreshSpec struct {
 	JWEToken string `json:"jweToken"`
 }
 
+// LogoutSpec contains token that is revoked by logout operation.
+type LogoutSpec struct {
+	// JWEToken is a token generated during login request that should not be accepted anymore.
+	JWEToken string `json:"jweToken"`
+}
+
 // AuthResponse represents the response returned from k8sconsole backend for login requests. It contains generated
 // JWEToken and a list of non-critical errors such as 'Failed authentication' to tell the frontend what unexpected
 // happened during login request.
@@ -128,6 +182,13 @@ type LoginModesResponse struct {
 	Modes []AuthenticationMode `json:"modes"`
 }
 
+// OIDCLoginResponse contains URL of OpenID Connect issuer user should be redirected to in order to log in.
+// After authorization issuer redirects user back with 'code' and 'state' parameters, which are then sent
+// with login request.
+type OIDCLoginResponse struct {
+	AuthURL string `json:"authURL"`
+}
+
 // LoginSkippableResponse contains a flag that tells the frontend not to display the 'auth skip' button
 // It's just for hide the button, not disable unauthenticated access
 type LoginSkippableResponse struct {
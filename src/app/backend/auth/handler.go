@@ -5,8 +5,12 @@ import (
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
 	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
 	"net/http"
+	"path"
 )
 
+// Time (in sec) user has to finish authorization by OpenID Connect issuer in.
+const oidcNonceMaxAge = 600
+
 type AuthHandler struct {
 	manager authApi.AuthManager
 }
@@ -22,10 +26,18 @@ func (self AuthHandler) Install(ws *restful.WebService) {
 			To(self.handleJWETokenRefresh).
 			Reads(authApi.TokenRefreshSpec{}).
 			Writes(authApi.AuthResponse{}))
+	ws.Route(
+		ws.POST("/logout").
+			To(self.handleLogout).
+			Reads(authApi.LogoutSpec{}))
 	ws.Route(
 		ws.GET("/login/modes").
 			To(self.handleLoginModes).
 			Writes(authApi.LoginModesResponse{}))
+	ws.Route(
+		ws.GET("/login/oidc").
+			To(self.handleOIDCLogin).
+			Writes(authApi.OIDCLoginResponse{}))
 	ws.Route(
 		ws.GET("/login/skippable").
 			To(self.handleLoginSkippable).
@@ -40,6 +52,14 @@ func (self AuthHandler) handleLogin(request *restful.Request, response *restful.
 		return
 	}
 
+	if len(loginSpec.OIDCCode) > 0 {
+		if cookie, err := request.Request.Cookie(OIDCNonceCookie); err == nil {
+			loginSpec.OIDCNonce = cookie.Value
+		}
+		// Nonce can be used only once.
+		setOIDCNonceCookie(request, response, request.Request.URL.Path, "", -1)
+	}
+
 	loginResponse, err := self.manager.Login(loginSpec)
 	if err != nil {
 		response.AddHeader("Content-Type", "text/plain")
@@ -71,14 +91,66 @@ func (self *AuthHandler) handleJWETokenRefresh(request *restful.Request, respons
 	})
 }
 
+func (self *AuthHandler) handleLogout(request *restful.Request, response *restful.Response) {
+	logoutSpec := new(authApi.LogoutSpec)
+	if err := request.ReadEntity(logoutSpec); err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(kcErrors.HandleHTTPError(err), err.Error()+"\n")
+		return
+	}
+
+	if err := self.manager.Logout(logoutSpec.JWEToken); err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(kcErrors.HandleHTTPError(err), err.Error()+"\n")
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
 func (self *AuthHandler) handleLoginModes(request *restful.Request, response *restful.Response) {
 	response.WriteHeaderAndEntity(http.StatusOK, authApi.LoginModesResponse{Modes: self.manager.AuthenticationModes()})
 }
 
+func (self *AuthHandler) handleOIDCLogin(request *restful.Request, response *restful.Response) {
+	nonce, err := NewOIDCNonce()
+	if err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(kcErrors.HandleHTTPError(err), err.Error()+"\n")
+		return
+	}
+
+	authURL, err := self.manager.OIDCAuthURL(nonce)
+	if err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(kcErrors.HandleHTTPError(err), err.Error()+"\n")
+		return
+	}
+
+	// Login route is the parent of OIDC login route.
+	setOIDCNonceCookie(request, response, path.Dir(request.Request.URL.Path), nonce, oidcNonceMaxAge)
+	response.WriteHeaderAndEntity(http.StatusOK, authApi.OIDCLoginResponse{AuthURL: authURL})
+}
+
 func (self *AuthHandler) handleLoginSkippable(request *restful.Request, response *restful.Response) {
 	response.WriteHeaderAndEntity(http.StatusOK, authApi.LoginSkippableResponse{Skippable: self.manager.AuthenticationSkippable()})
 }
 
+// Sets cookie with nonce of OIDC state. It is sent only with requests to given login path. Negative max age
+// removes the cookie.
+func setOIDCNonceCookie(request *restful.Request, response *restful.Response, loginPath, nonce string,
+	maxAge int) {
+	http.SetCookie(response, &http.Cookie{
+		Name:     OIDCNonceCookie,
+		Value:    nonce,
+		Path:     loginPath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   request.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
 // NewAuthHandler created AuthHandler instance.
 func NewAuthHandler(manager authApi.AuthManager) AuthHandler {
 	return AuthHandler{manager: manager}
@@ -3,25 +3,70 @@ package jwe
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"github.com/golang/glog"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
 	"gopkg.in/square/go-jose.v2"
+	"k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	// Key of the secret data entry that holds PEM encoded private key. Keys of the keyring are stored
+	// in entries with key ID appended, i.e. 'priv.20180601T120000Z'. Entry without key ID holds the key
+	// created before key rotation was introduced.
+	holderMapKeyEntry = "priv"
+	// Key of the secret data entry that holds ID of the key used to encrypt new tokens.
+	holderMapCurrentKeyIDEntry = "kid"
+	// Key IDs are creation times of the keys, so they can be sorted and used to find out the key age.
+	keyIDFormat = "20060102T150405Z"
+
+	// Period after which the secret watch is restarted when it has been closed.
+	watchRestartPeriod = 5 * time.Second
+	// Maximal period between checks whether current key should be rotated.
+	maxRotationCheckPeriod = time.Minute
+
+	// Number of keys kept for decryption by default.
+	DefaultKeyRingSize = 3
 )
 
 // KeyHolder is responsible for generating, storing and sync encryption key used for token generation/decryption.
 type KeyHolder interface {
-	// Returns encrypter instance that can be used to encrypt data.
+	// Returns encrypter instance that can be used to encrypt data. Encrypted data contains ID of the current key
+	// in its header.
 	Encrypter() jose.Encrypter
-	// Returns encryption key that can be used to decrypt data.
+	// Returns current encryption key that can be used to decrypt data.
 	Key() *rsa.PrivateKey
+	// Returns key with given ID from the keyring or nil if there is no such key.
+	KeyByID(string) *rsa.PrivateKey
 	// Forces refresh of encryption key sync with k8s resource (secret).
 	Refresh()
 }
 
-// Implements KeyHolder interface.
+// Implements KeyHolder interface. Keyring is stored in a secret, so all k8sconsole replicas share the same
+// keys and tokens stay valid after restart. Current key is replaced every rotation period, previous keys
+// are kept for decryption until keyring size is exceeded.
 type rsaKeyHolder struct {
-	key *rsa.PrivateKey
-	mux sync.Mutex
+	client    kubernetes.Interface
+	name      string
+	namespace string
+
+	rotationPeriod time.Duration
+	keyRingSize    int
+
+	keys         map[string]*rsa.PrivateKey
+	currentKeyID string
+	mux          sync.Mutex
 }
 
 // Encrypter implements key holder interface.
@@ -29,10 +74,14 @@ type rsaKeyHolder struct {
 //    - Content encryption: AES-GCM (256)
 //    - Key management: RSA-OAEP-SHA256
 func (self *rsaKeyHolder) Encrypter() jose.Encrypter {
-	publicKey := &self.Key().PublicKey
+	self.mux.Lock()
+	publicKey := &self.keys[self.currentKeyID].PublicKey
+	keyID := self.currentKeyID
+	self.mux.Unlock()
+
 	encrypter, err := jose.NewEncrypter(jose.A256GCM,
 		jose.Recipient{
-			Algorithm: jose.RSA_OAEP_256, Key: publicKey}, nil)
+			Algorithm: jose.RSA_OAEP_256, Key: publicKey, KeyID: keyID}, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -45,33 +94,292 @@ func (self *rsaKeyHolder) Encrypter() jose.Encrypter {
 func (self *rsaKeyHolder) Key() *rsa.PrivateKey {
 	self.mux.Lock()
 	defer self.mux.Unlock()
-	return self.key
+	return self.keys[self.currentKeyID]
 }
 
-//TODO(wzt3309) sync with k8s resource.
-func (self *rsaKeyHolder) Refresh() {}
+// KeyByID implements key holder interface.
+func (self *rsaKeyHolder) KeyByID(keyID string) *rsa.PrivateKey {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return self.keys[keyID]
+}
+
+// Refresh implements key holder interface. Reads keyring stored in the secret.
+func (self *rsaKeyHolder) Refresh() {
+	secret, err := self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+	if err != nil {
+		glog.Errorf("Could not refresh JWE encryption key: %s", err.Error())
+		return
+	}
+
+	self.update(secret)
+}
 
-//TODO(wzt3309) sync init encryption key with k8s resource (secret).
 func (self *rsaKeyHolder) init() {
 	self.initEncryptionKey()
+	go wait.Forever(self.watch, watchRestartPeriod)
+
+	if self.rotationPeriod > 0 {
+		checkPeriod := self.rotationPeriod / 10
+		if checkPeriod > maxRotationCheckPeriod {
+			checkPeriod = maxRotationCheckPeriod
+		}
+
+		go wait.Forever(self.rotateIfExpired, checkPeriod)
+	}
 }
 
+// Loads keyring from the secret or generates a new key and stores it if the secret does not exist.
 func (self *rsaKeyHolder) initEncryptionKey() {
+	secret, err := self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+	if err == nil {
+		glog.Infof("Using JWE encryption keys from %s/%s secret", self.namespace, self.name)
+		self.update(secret)
+		return
+	}
+
+	if !k8sErrors.IsNotFound(err) {
+		panic(err)
+	}
+
 	glog.Info("Generating JWE encryption key")
+	keyID, privateKey, err := self.generateKey()
+	if err != nil {
+		panic(err)
+	}
+
+	self.mux.Lock()
+	self.keys = map[string]*rsa.PrivateKey{keyID: privateKey}
+	self.currentKeyID = keyID
+	self.mux.Unlock()
+
+	secret, err = self.client.CoreV1().Secrets(self.namespace).Create(self.getSecret())
+	if k8sErrors.IsAlreadyExists(err) {
+		// Other replica has stored its key in the meantime, use it instead.
+		self.Refresh()
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	glog.Infof("Stored JWE encryption key in %s/%s secret", self.namespace, self.name)
+}
+
+// Watches the secret and keeps keyring in sync with it. Returns when the watch is closed.
+func (self *rsaKeyHolder) watch() {
+	watcher, err := self.client.CoreV1().Secrets(self.namespace).Watch(metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", self.name).String(),
+	})
+	if err != nil {
+		glog.Errorf("Could not watch JWE encryption key: %s", err.Error())
+		return
+	}
+	defer watcher.Stop()
+
+	for event := range watcher.ResultChan() {
+		secret, ok := event.Object.(*v1.Secret)
+		if !ok || secret.Name != self.name {
+			continue
+		}
+
+		switch event.Type {
+		case watch.Added, watch.Modified:
+			self.update(secret)
+		case watch.Deleted:
+			self.recreate()
+		}
+	}
+}
+
+// Rotates current key if it is older than rotation period. Secret is updated with optimistic concurrency,
+// so only one replica rotates the key and others pick it up from the secret.
+func (self *rsaKeyHolder) rotateIfExpired() {
+	secret, err := self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+	if err != nil {
+		glog.Errorf("Could not check JWE encryption key age: %s", err.Error())
+		return
+	}
+
+	self.update(secret)
+	created, err := time.Parse(keyIDFormat, string(secret.Data[holderMapCurrentKeyIDEntry]))
+	if err == nil && time.Since(created) < self.rotationPeriod {
+		return
+	}
+
+	if err := self.rotate(secret); err != nil {
+		glog.Errorf("Could not rotate JWE encryption key: %s", err.Error())
+	}
+}
+
+// Generates new current key and removes the oldest keys that exceed keyring size. The given secret
+// has to be the latest known version of the keyring secret.
+func (self *rsaKeyHolder) rotate(secret *v1.Secret) error {
+	keyID, privateKey, err := self.generateKey()
+	if err != nil {
+		return err
+	}
+
+	keys, _, err := self.parseKeys(secret)
+	if err != nil {
+		return err
+	}
+
+	keys[keyID] = privateKey
+	for _, oldKeyID := range sortedKeyIDs(keys) {
+		if len(keys) <= self.keyRingSize {
+			break
+		}
+
+		delete(keys, oldKeyID)
+	}
+
+	rotated := secret.DeepCopy()
+	rotated.Data = getSecretData(keys, keyID)
+	updated, err := self.client.CoreV1().Secrets(self.namespace).Update(rotated)
+	if k8sErrors.IsConflict(err) {
+		glog.Info("JWE encryption key has been rotated by other replica")
+		self.Refresh()
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("Rotated JWE encryption key, new key ID: %s", keyID)
+	self.update(updated)
+	return nil
+}
+
+// Updates keyring with the keys stored in the given secret.
+func (self *rsaKeyHolder) update(secret *v1.Secret) {
+	keys, currentKeyID, err := self.parseKeys(secret)
+	if err != nil {
+		glog.Errorf("Could not read JWE encryption key from %s/%s secret: %s", self.namespace, self.name,
+			err.Error())
+		return
+	}
+
 	self.mux.Lock()
 	defer self.mux.Unlock()
+	self.keys = keys
+	self.currentKeyID = currentKeyID
+}
+
+// Stores current keyring in the secret after it has been deleted.
+func (self *rsaKeyHolder) recreate() {
+	glog.Infof("JWE encryption key secret %s/%s has been deleted, recreating it", self.namespace, self.name)
+	_, err := self.client.CoreV1().Secrets(self.namespace).Create(self.getSecret())
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		glog.Errorf("Could not recreate JWE encryption key secret: %s", err.Error())
+	}
+}
+
+// Returns keys stored in the secret mapped by their IDs and ID of the current key.
+func (self *rsaKeyHolder) parseKeys(secret *v1.Secret) (map[string]*rsa.PrivateKey, string, error) {
+	keys := make(map[string]*rsa.PrivateKey)
+	for entry, data := range secret.Data {
+		if entry != holderMapKeyEntry && !strings.HasPrefix(entry, holderMapKeyEntry+".") {
+			continue
+		}
+
+		privateKey, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, "", err
+		}
+
+		keys[strings.TrimPrefix(strings.TrimPrefix(entry, holderMapKeyEntry), ".")] = privateKey
+	}
+
+	currentKeyID := string(secret.Data[holderMapCurrentKeyIDEntry])
+	if _, exists := keys[currentKeyID]; !exists {
+		return nil, "", errors.New("Current key not found in keyring.")
+	}
+
+	return keys, currentKeyID, nil
+}
 
+func (self *rsaKeyHolder) generateKey() (string, *rsa.PrivateKey, error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
-		panic(err)
+		return "", nil, err
+	}
+
+	return time.Now().UTC().Format(keyIDFormat), privateKey, nil
+}
+
+func (self *rsaKeyHolder) getSecret() *v1.Secret {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	return &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      self.name,
+			Namespace: self.namespace,
+		},
+		Data: getSecretData(self.keys, self.currentKeyID),
+	}
+}
+
+func getSecretData(keys map[string]*rsa.PrivateKey, currentKeyID string) map[string][]byte {
+	data := map[string][]byte{
+		holderMapCurrentKeyIDEntry: []byte(currentKeyID),
+	}
+
+	for keyID, privateKey := range keys {
+		entry := holderMapKeyEntry
+		if len(keyID) > 0 {
+			entry += "." + keyID
+		}
+
+		data[entry] = cert.EncodePrivateKeyPEM(privateKey)
 	}
 
-	self.key = privateKey
+	return data
 }
 
-// NewRSAKeyHolder creates new KeyHolder instance.
-func NewRSAKeyHolder() KeyHolder {
-	holder := &rsaKeyHolder{}
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	key, err := cert.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("Stored key is not a RSA private key.")
+	}
+
+	return privateKey, nil
+}
+
+// Returns key IDs sorted from the oldest to the newest key.
+func sortedKeyIDs(keys map[string]*rsa.PrivateKey) []string {
+	keyIDs := make([]string, 0, len(keys))
+	for keyID := range keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	sort.Strings(keyIDs)
+	return keyIDs
+}
+
+// NewRSAKeyHolder creates new KeyHolder instance. Keyring is stored in a secret and kept in sync
+// with it using given client. If rotation period is greater than 0, current key is replaced every period
+// and last keyRingSize keys are kept for decryption.
+func NewRSAKeyHolder(client kubernetes.Interface, rotationPeriod time.Duration, keyRingSize int) KeyHolder {
+	if keyRingSize < 1 {
+		keyRingSize = DefaultKeyRingSize
+	}
+
+	holder := &rsaKeyHolder{
+		client:         client,
+		name:           authApi.EncrytionKeyHolderName,
+		namespace:      authApi.EncryptionKeyHolderNamespace,
+		rotationPeriod: rotationPeriod,
+		keyRingSize:    keyRingSize,
+	}
 
 	holder.init()
 	return holder
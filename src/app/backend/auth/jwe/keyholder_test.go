@@ -0,0 +1,99 @@
+package jwe
+
+import (
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"reflect"
+	"testing"
+)
+
+func TestRSAKeyHolder_SharedKey(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	first := NewRSAKeyHolder(client, 0, DefaultKeyRingSize)
+	second := NewRSAKeyHolder(client, 0, DefaultKeyRingSize)
+
+	if !reflect.DeepEqual(first.Key(), second.Key()) {
+		t.Fatal("NewRSAKeyHolder(): Expected key holders to share encryption key stored in secret")
+	}
+
+	authInfo := api.AuthInfo{Token: "test-token"}
+	token, err := NewJWETokenManager(first, nil).Generate(authInfo)
+	if err != nil {
+		t.Fatalf("Generate(): Unexpected error: %s", err)
+	}
+
+	decrypted, err := NewJWETokenManager(second, nil).Decrypt(token)
+	if err != nil {
+		t.Fatalf("Decrypt(): Expected token to be decrypted by other replica but error was thrown: %s", err)
+	}
+
+	if !reflect.DeepEqual(*decrypted, authInfo) {
+		t.Errorf("Decrypt(): Expected: %v, but got %v.", authInfo, *decrypted)
+	}
+}
+
+func TestRSAKeyHolder_Rotate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	// Secret watch is not started, so keyring changes only on explicit updates.
+	holder := &rsaKeyHolder{
+		client:      client,
+		name:        authApi.EncrytionKeyHolderName,
+		namespace:   authApi.EncryptionKeyHolderNamespace,
+		keyRingSize: 2,
+	}
+	holder.initEncryptionKey()
+	tokenManager := NewJWETokenManager(holder, nil)
+
+	// Key IDs are based on creation time, so current key is renamed to make sure it differs from the new one.
+	renameCurrentKey := func(keyID string) *v1.Secret {
+		secret, err := client.CoreV1().Secrets(holder.namespace).Get(holder.name, metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		currentEntry := holderMapKeyEntry + "." + string(secret.Data[holderMapCurrentKeyIDEntry])
+		secret.Data[holderMapKeyEntry+"."+keyID] = secret.Data[currentEntry]
+		delete(secret.Data, currentEntry)
+		secret.Data[holderMapCurrentKeyIDEntry] = []byte(keyID)
+		secret, err = client.CoreV1().Secrets(holder.namespace).Update(secret)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		holder.update(secret)
+		return secret
+	}
+
+	authInfo := api.AuthInfo{Token: "test-token"}
+	secret := renameCurrentKey("20000101T000000Z")
+	token, _ := tokenManager.Generate(authInfo)
+
+	if err := holder.rotate(secret); err != nil {
+		t.Fatalf("rotate(): Unexpected error: %s", err)
+	}
+
+	if len(holder.keys) != 2 || holder.currentKeyID == "20000101T000000Z" {
+		t.Fatalf("rotate(): Expected new current key and 2 keys in keyring, but got %s and %d keys",
+			holder.currentKeyID, len(holder.keys))
+	}
+
+	if _, err := tokenManager.Decrypt(token); err != nil {
+		t.Errorf("Decrypt(): Expected token encrypted with previous key to be valid, but got %s", err)
+	}
+
+	secret = renameCurrentKey("20010101T000000Z")
+	if err := holder.rotate(secret); err != nil {
+		t.Fatalf("rotate(): Unexpected error: %s", err)
+	}
+
+	if len(holder.keys) != 2 || holder.KeyByID("20000101T000000Z") != nil {
+		t.Fatalf("rotate(): Expected the oldest key to be removed from keyring, but got %d keys", len(holder.keys))
+	}
+
+	if _, err := tokenManager.Decrypt(token); err == nil {
+		t.Error("Decrypt(): Expected token encrypted with removed key to be rejected")
+	}
+}
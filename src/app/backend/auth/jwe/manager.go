@@ -1,6 +1,8 @@
 package jwe
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
@@ -12,8 +14,17 @@ import (
 
 // Implements TokenManager interface.
 type jweTokenManager struct {
-	keyHolder KeyHolder
-	tokenTTL  time.Duration
+	keyHolder   KeyHolder
+	revocations RevocationList
+	tokenTTL    time.Duration
+}
+
+// Payload of the token. Auth info used to access the default cluster is embedded, so tokens generated
+// before multi-cluster support was introduced can still be decrypted.
+type tokenPayload struct {
+	api.AuthInfo
+	// Auth infos used to access additional clusters mapped by cluster names.
+	Clusters map[string]api.AuthInfo `json:"clusters,omitempty"`
 }
 
 // AdditionalAuthData contains information required to validate token.
@@ -28,17 +39,38 @@ const (
 	BOR Claim = "iat"
 	// EXP claim is part of token AAD header. It represents token expiration time.
 	EXP Claim = "exp"
+	// JTI claim is part of token AAD header. It represents unique token ID used for token revocation.
+	JTI Claim = "jti"
+
+	// Length (in bytes) of random token ID.
+	tokenIDLength = 16
+
+	// Time refreshed token stays valid for, so requests of other browser tabs sent with it at the same time
+	// are not rejected.
+	refreshGracePeriod = 30 * time.Second
 )
 
 // Generate adn encrypt JWE token based on provided AuthInfo. AuthInfo will be embedded in a token payload and
 // encrypted with autogenerated signing key.
 func (self *jweTokenManager) Generate(authInfo api.AuthInfo) (string, error) {
-	marshalledAuthInfo, err := json.Marshal(authInfo)
+	return self.GenerateWithClusters(authInfo, nil)
+}
+
+// GenerateWithClusters implements TokenManager interface. Auth infos of additional clusters are embedded
+// in a token payload together with auth info of the default cluster.
+func (self *jweTokenManager) GenerateWithClusters(authInfo api.AuthInfo,
+	clusters map[string]api.AuthInfo) (string, error) {
+	marshalledAuthInfo, err := json.Marshal(tokenPayload{AuthInfo: authInfo, Clusters: clusters})
 	if err != nil {
 		return "", err
 	}
 
-	jweObject, err := self.getEncrypter().EncryptWithAuthData(marshalledAuthInfo, self.generateADD())
+	aad, err := self.generateADD()
+	if err != nil {
+		return "", err
+	}
+
+	jweObject, err := self.getEncrypter().EncryptWithAuthData(marshalledAuthInfo, aad)
 	if err != nil {
 		return "", err
 	}
@@ -48,26 +80,32 @@ func (self *jweTokenManager) Generate(authInfo api.AuthInfo) (string, error) {
 
 // Decrypt provides token and returns AuthInfo saved in a token payload.
 func (self *jweTokenManager) Decrypt(jweToken string) (*api.AuthInfo, error) {
+	authInfo, _, err := self.DecryptWithClusters(jweToken)
+	return authInfo, err
+}
+
+// DecryptWithClusters implements TokenManager interface.
+func (self *jweTokenManager) DecryptWithClusters(jweToken string) (*api.AuthInfo, map[string]api.AuthInfo, error) {
 	jweTokenObject, err := self.validate(jweToken)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	decryted, err := jweTokenObject.Decrypt(self.keyHolder.Key())
-	if err == jose.ErrCryptoFailure {
-		// Force key refresh and try to decrypt again
-		// TODO(wzt3309) Now keyHolder refresh method is not ready for use
-		self.keyHolder.Refresh()
-		decryted, err = jweTokenObject.Decrypt(self.keyHolder.Key())
+	decrypted, err := self.decrypt(jweTokenObject)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err != nil {
-		return nil, err
+	payload := new(tokenPayload)
+	if err = json.Unmarshal(decrypted, payload); err != nil {
+		return nil, nil, err
 	}
 
-	authInfo := new(api.AuthInfo)
-	err = json.Unmarshal(decryted, authInfo)
-	return authInfo, err
+	if payload.Clusters == nil {
+		payload.Clusters = make(map[string]api.AuthInfo)
+	}
+
+	return &payload.AuthInfo, payload.Clusters, nil
 }
 
 // Refresh implements TokenManager interface
@@ -81,18 +119,81 @@ func (self *jweTokenManager) Refresh(jweToken string) (string, error) {
 		return "", err
 	}
 
-	decrypted, err := jweTokenObject.Decrypt(self.keyHolder.Key())
+	decrypted, err := self.decrypt(jweTokenObject)
 	if err != nil {
 		return "", err
 	}
 
-	authInfo := new(api.AuthInfo)
-	err = json.Unmarshal(decrypted, authInfo)
+	payload := new(tokenPayload)
+	err = json.Unmarshal(decrypted, payload)
 	if err != nil {
 		return "", errors.New("Token refresh error. Could not unmarshal token payload.")
 	}
 
-	return self.Generate(*authInfo)
+	refreshedToken, err := self.GenerateWithClusters(payload.AuthInfo, payload.Clusters)
+	if err != nil {
+		return "", err
+	}
+
+	// Refreshed token gets new ID, so the old one has to be revoked. Otherwise it would stay valid after logout.
+	if err = self.RevokeRefreshed(jweToken); err != nil {
+		return "", err
+	}
+
+	return refreshedToken, nil
+}
+
+// Revoke implements TokenManager interface. Token is decrypted first to make sure it has been generated
+// by k8sconsole. Expired tokens are not added to the revocation list as they are rejected anyway.
+func (self *jweTokenManager) Revoke(jweToken string) error {
+	if len(jweToken) == 0 {
+		return errors.New("Can not revoke token. No token provided.")
+	}
+
+	return self.revoke(jweToken, time.Time{})
+}
+
+// RevokeRefreshed implements TokenManager interface. Tokens that never expire are not revoked, as their IDs
+// would have to be kept forever, and neither are tokens that expire within the grace period.
+func (self *jweTokenManager) RevokeRefreshed(jweToken string) error {
+	return self.revoke(jweToken, time.Now().Add(refreshGracePeriod))
+}
+
+// Adds ID of the token to the revocation list, so it is rejected from given time on. Zero time means that it is
+// rejected immediately. Tokens revoked later are only added if they expire after that time.
+func (self *jweTokenManager) revoke(jweToken string, from time.Time) error {
+	jweTokenObject, err := self.validate(jweToken)
+	if err != nil {
+		if err.Error() == kcErrors.MSG_TOKEN_EXPIRED_ERROR || err.Error() == kcErrors.MSG_TOKEN_REVOKED_ERROR {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := self.decrypt(jweTokenObject); err != nil {
+		return err
+	}
+
+	aad, err := self.getAAD(jweTokenObject)
+	if err != nil {
+		return err
+	}
+
+	if len(aad[JTI]) == 0 {
+		return errors.New("Can not revoke token. Token does not contain token ID.")
+	}
+
+	// Token generated without TTL never expires, so its ID has to be kept forever
+	expiry, err := time.Parse(timeFormat, aad[EXP])
+	if err != nil {
+		expiry = time.Time{}
+	}
+
+	if !from.IsZero() && (expiry.IsZero() || !expiry.After(from)) {
+		return nil
+	}
+
+	return self.revocations.Revoke(aad[JTI], from, expiry)
 }
 
 // SetTokenTTL implements TokenManager interface
@@ -104,32 +205,74 @@ func (self *jweTokenManager) SetTokenTTL(ttl time.Duration) {
 	self.tokenTTL = ttl * time.Second
 }
 
+// TokenTTL implements TokenManager interface
+func (self *jweTokenManager) TokenTTL() time.Duration {
+	return self.tokenTTL
+}
+
+// Decrypts token with the key identified by the key ID from token header. Keyring is refreshed
+// in case the key is not known yet, i.e. it has been rotated by other replica.
+func (self *jweTokenManager) decrypt(jweTokenObject *jose.JSONWebEncryption) ([]byte, error) {
+	keyID := jweTokenObject.Header.KeyID
+	key := self.keyHolder.KeyByID(keyID)
+	if key == nil {
+		self.keyHolder.Refresh()
+		key = self.keyHolder.KeyByID(keyID)
+	}
+
+	if key == nil {
+		return nil, errors.New("Token decryption error. Encryption key has expired.")
+	}
+
+	decrypted, err := jweTokenObject.Decrypt(key)
+	if err == jose.ErrCryptoFailure {
+		// Force key refresh and try to decrypt again
+		self.keyHolder.Refresh()
+		if key = self.keyHolder.KeyByID(keyID); key != nil {
+			decrypted, err = jweTokenObject.Decrypt(key)
+		}
+	}
+
+	return decrypted, err
+}
+
 func (self *jweTokenManager) getEncrypter() jose.Encrypter {
 	return self.keyHolder.Encrypter()
 }
 
-// Parses and validates provided token to check if it is expired
+// Parses and validates provided token to check if it is expired or revoked
 func (self *jweTokenManager) validate(jweToken string) (*jose.JSONWebEncryption, error) {
 	jwe, err := jose.ParseEncrypted(jweToken)
 	if err != nil {
 		return nil, err
 	}
 
-	if self.tokenTTL > 0 {
-		aad := AdditionalAuthData{}
-		err = json.Unmarshal(jwe.GetAuthData(), &aad)
-		if err != nil {
-			return nil, errors.New("Token validation error. Could not unmarshal AAD.")
-		}
+	aad, err := self.getAAD(jwe)
+	if err != nil {
+		return nil, err
+	}
 
-		if self.isExpired(aad[BOR], aad[EXP]) {
-			return nil, errors.New(kcErrors.MSG_TOKEN_EXPIRED_ERROR)
-		}
+	if self.tokenTTL > 0 && self.isExpired(aad[BOR], aad[EXP]) {
+		return nil, errors.New(kcErrors.MSG_TOKEN_EXPIRED_ERROR)
+	}
+
+	// Tokens generated before token IDs were introduced can not be revoked
+	if len(aad[JTI]) > 0 && self.revocations.IsRevoked(aad[JTI]) {
+		return nil, errors.New(kcErrors.MSG_TOKEN_REVOKED_ERROR)
 	}
 
 	return jwe, nil
 }
 
+func (self *jweTokenManager) getAAD(jwe *jose.JSONWebEncryption) (AdditionalAuthData, error) {
+	aad := AdditionalAuthData{}
+	if err := json.Unmarshal(jwe.GetAuthData(), &aad); err != nil {
+		return nil, errors.New("Token validation error. Could not unmarshal AAD.")
+	}
+
+	return aad, nil
+}
+
 // Retures true if token has expired.
 // If the time string can't be parsed into time, the token will be marked as expired.
 func (self *jweTokenManager) isExpired(borStr, expStr string) bool {
@@ -147,22 +290,36 @@ func (self *jweTokenManager) isExpired(borStr, expStr string) bool {
 	return bor.Add(age).After(exp)
 }
 
-func (self *jweTokenManager) generateADD() []byte {
+func (self *jweTokenManager) generateADD() ([]byte, error) {
+	tokenID := make([]byte, tokenIDLength)
+	if _, err := rand.Read(tokenID); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	aad := AdditionalAuthData{
 		BOR: now.Format(timeFormat),
+		JTI: hex.EncodeToString(tokenID),
 	}
 
 	if self.tokenTTL > 0 {
 		aad[EXP] = now.Add(self.tokenTTL).Format(timeFormat)
 	}
 
-	rawAAD, _ := json.Marshal(aad)
-	return rawAAD
+	return json.Marshal(aad)
 }
 
-// Creates and returns default JWE Token manager interface
-func NewJWETokenManager(holder KeyHolder) authApi.TokenManager {
-	manager := &jweTokenManager{keyHolder: holder, tokenTTL: authApi.DefaultTokenTTL * time.Second}
+// Creates and returns default JWE Token manager interface. If revocation list is nil, revoked tokens
+// are kept only in memory.
+func NewJWETokenManager(holder KeyHolder, revocations RevocationList) authApi.TokenManager {
+	if revocations == nil {
+		revocations = NewRevocationList()
+	}
+
+	manager := &jweTokenManager{
+		keyHolder:   holder,
+		revocations: revocations,
+		tokenTTL:    authApi.DefaultTokenTTL * time.Second,
+	}
 	return manager
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
 	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/clientcmd/api"
 	"reflect"
 	"testing"
@@ -11,8 +12,8 @@ import (
 )
 
 func getTokenManager() authApi.TokenManager {
-	holder := NewRSAKeyHolder()
-	return NewJWETokenManager(holder)
+	holder := NewRSAKeyHolder(fake.NewSimpleClientset(), 0, DefaultKeyRingSize)
+	return NewJWETokenManager(holder, nil)
 }
 
 func asSameError(err1, err2 error) bool {
@@ -130,8 +131,102 @@ func TestJweTokenManager_Refresh(t *testing.T) {
 				c.info, c.expectedErr, err)
 		}
 
+		if _, err := tokenManager.Decrypt(token); c.expected && err != nil {
+			t.Errorf("Test case: %s. Expected refreshed token to stay valid for grace period, but got %v.",
+				c.info, err)
+		}
+
 		if (c.expected && len(refreshToken) == 0) || (!c.expected && len(refreshToken) > 0) {
 			t.Errorf("Test Case: %s. Expected new token to be generated: %t", c.info, c.expected)
 		}
 	}
 }
+
+func TestJweTokenManager_RevokeRefreshed(t *testing.T) {
+	cases := []struct {
+		ttl      time.Duration
+		expected bool
+	}{
+		{3600, true},
+		// Token expires within the grace period
+		{1, false},
+		// Token never expires
+		{0, false},
+	}
+
+	for _, c := range cases {
+		revocations := NewRevocationList().(*revocationList)
+		tokenManager := NewJWETokenManager(NewRSAKeyHolder(fake.NewSimpleClientset(), 0, DefaultKeyRingSize),
+			revocations)
+		tokenManager.SetTokenTTL(c.ttl)
+		token, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+
+		if _, err := tokenManager.Refresh(token); err != nil {
+			t.Fatalf("Refresh(ttl=%d): Unexpected error: %s", c.ttl, err)
+		}
+
+		if _, err := tokenManager.Decrypt(token); err != nil {
+			t.Errorf("Decrypt(ttl=%d): Expected refreshed token to stay valid but got %v", c.ttl, err)
+		}
+
+		if len(revocations.revoked) == 1 != c.expected {
+			t.Errorf("Refresh(ttl=%d): Expected token revocation to be scheduled: %t, but got %v", c.ttl,
+				c.expected, revocations.revoked)
+		}
+
+		for tokenID, entry := range revocations.revoked {
+			entry.from = time.Now()
+			revocations.revoked[tokenID] = entry
+		}
+
+		expectedErr := errors.New(kcErrors.MSG_TOKEN_REVOKED_ERROR)
+		if _, err := tokenManager.Decrypt(token); c.expected && !asSameError(expectedErr, err) {
+			t.Errorf("Decrypt(ttl=%d): Expected token to be revoked after grace period but got %v", c.ttl, err)
+		}
+	}
+}
+
+func TestJweTokenManager_Revoke(t *testing.T) {
+	tokenManager := getTokenManager()
+	token, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+	otherToken, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+
+	if err := tokenManager.Revoke(token); err != nil {
+		t.Fatalf("Revoke(): Expected token to be revoked but error was thrown: %s", err)
+	}
+
+	expectedErr := errors.New(kcErrors.MSG_TOKEN_REVOKED_ERROR)
+	if _, err := tokenManager.Decrypt(token); !asSameError(expectedErr, err) {
+		t.Errorf("Decrypt(): Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+
+	if _, err := tokenManager.Refresh(token); !asSameError(expectedErr, err) {
+		t.Errorf("Refresh(): Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+
+	if _, err := tokenManager.Decrypt(otherToken); err != nil {
+		t.Errorf("Decrypt(): Expected other token to stay valid but error was thrown: %s", err)
+	}
+
+	if err := tokenManager.Revoke(token); err != nil {
+		t.Errorf("Revoke(): Expected revoking revoked token to succeed but error was thrown: %s", err)
+	}
+}
+
+func TestSecretRevocationList_Shared(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	holder := NewRSAKeyHolder(client, 0, DefaultKeyRingSize)
+	first := NewJWETokenManager(holder, NewSecretRevocationList(client))
+
+	token, _ := first.Generate(api.AuthInfo{Token: "test-token"})
+	if err := first.Revoke(token); err != nil {
+		t.Fatalf("Revoke(): Expected token to be revoked but error was thrown: %s", err)
+	}
+
+	// Revocation list of other replica is loaded from the secret
+	second := NewJWETokenManager(holder, NewSecretRevocationList(client))
+	expectedErr := errors.New(kcErrors.MSG_TOKEN_REVOKED_ERROR)
+	if _, err := second.Decrypt(token); !asSameError(expectedErr, err) {
+		t.Errorf("Decrypt(): Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+}
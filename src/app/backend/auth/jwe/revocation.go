@@ -0,0 +1,249 @@
+package jwe
+
+import (
+	"github.com/golang/glog"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Number of attempts to store revoked token in the secret in case of update conflicts.
+const maxRevocationUpdateRetries = 5
+
+// Separates expiration time of revoked token from the time it is revoked from in values of the secret.
+const revocationSeparator = ","
+
+// RevocationList keeps IDs of tokens that have been revoked before they expired, i.e. on logout.
+type RevocationList interface {
+	// Revoke adds token ID to the list. Token is rejected from given time on, zero time means immediately.
+	// Token ID is kept until given expiration time, zero time means that token never expires.
+	Revoke(tokenID string, from, expiry time.Time) error
+	// IsRevoked returns true if token with given ID has been revoked.
+	IsRevoked(tokenID string) bool
+}
+
+// Implements RevocationList interface. Revoked token IDs are kept in memory and, if the client is set,
+// stored in a secret and kept in sync with it, so all k8sconsole replicas share the same list.
+type revocationList struct {
+	client    kubernetes.Interface
+	name      string
+	namespace string
+
+	// Revoked tokens mapped by token IDs.
+	revoked map[string]revocation
+	mux     sync.Mutex
+}
+
+// Revocation of a single token.
+type revocation struct {
+	// From is the time the token is rejected from. Zero time means that it is rejected immediately.
+	from time.Time
+	// Expiry is the expiration time of the token. Zero time means that token never expires.
+	expiry time.Time
+}
+
+// Revoke implements RevocationList interface.
+func (self *revocationList) Revoke(tokenID string, from, expiry time.Time) error {
+	self.mux.Lock()
+	self.revoked[tokenID] = revocation{from: from, expiry: expiry}
+	self.prune(self.revoked)
+	self.mux.Unlock()
+
+	if self.client == nil {
+		return nil
+	}
+
+	return self.store()
+}
+
+// IsRevoked implements RevocationList interface.
+func (self *revocationList) IsRevoked(tokenID string) bool {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	entry, revoked := self.revoked[tokenID]
+	return revoked && !time.Now().Before(entry.from)
+}
+
+func (self *revocationList) init() {
+	secret, err := self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+	if err == nil {
+		self.update(secret)
+	} else if !k8sErrors.IsNotFound(err) {
+		glog.Errorf("Could not read token revocation list: %s", err.Error())
+	}
+
+	go wait.Forever(self.watch, watchRestartPeriod)
+}
+
+// Merges local list with the list stored in the secret and stores the result. Entries are never removed
+// from the secret until they expire, so concurrent updates of replicas are simply retried.
+func (self *revocationList) store() (err error) {
+	for i := 0; i < maxRevocationUpdateRetries; i++ {
+		secret, getErr := self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+		if k8sErrors.IsNotFound(getErr) {
+			_, err = self.client.CoreV1().Secrets(self.namespace).Create(self.getSecret(nil))
+		} else if getErr != nil {
+			return getErr
+		} else {
+			_, err = self.client.CoreV1().Secrets(self.namespace).Update(self.getSecret(secret))
+		}
+
+		if !k8sErrors.IsConflict(err) && !k8sErrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Watches the secret and merges revoked token IDs stored by other replicas. Returns when the watch is closed.
+func (self *revocationList) watch() {
+	watcher, err := self.client.CoreV1().Secrets(self.namespace).Watch(metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", self.name).String(),
+	})
+	if err != nil {
+		glog.Errorf("Could not watch token revocation list: %s", err.Error())
+		return
+	}
+	defer watcher.Stop()
+
+	for event := range watcher.ResultChan() {
+		secret, ok := event.Object.(*v1.Secret)
+		if !ok || secret.Name != self.name {
+			continue
+		}
+
+		switch event.Type {
+		case watch.Added, watch.Modified:
+			self.update(secret)
+		case watch.Deleted:
+			if err := self.store(); err != nil {
+				glog.Errorf("Could not recreate token revocation list: %s", err.Error())
+			}
+		}
+	}
+}
+
+// Adds token IDs stored in the secret to the local list.
+func (self *revocationList) update(secret *v1.Secret) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	for tokenID, entry := range parseRevocations(secret) {
+		self.revoked[tokenID] = entry
+	}
+
+	self.prune(self.revoked)
+}
+
+// Returns the given secret, or a new one if it is nil, with data containing both local and stored entries.
+func (self *revocationList) getSecret(secret *v1.Secret) *v1.Secret {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	revoked := make(map[string]revocation)
+	if secret == nil {
+		secret = &v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: self.name, Namespace: self.namespace}}
+	} else {
+		secret = secret.DeepCopy()
+		revoked = parseRevocations(secret)
+	}
+
+	for tokenID, entry := range self.revoked {
+		revoked[tokenID] = entry
+	}
+
+	self.prune(revoked)
+	secret.Data = make(map[string][]byte)
+	for tokenID, entry := range revoked {
+		secret.Data[tokenID] = []byte(formatRevocation(entry))
+	}
+
+	return secret
+}
+
+// Removes entries of tokens that have already expired. Such tokens are rejected anyway.
+func (self *revocationList) prune(revoked map[string]revocation) {
+	now := time.Now()
+	for tokenID, entry := range revoked {
+		if !entry.expiry.IsZero() && entry.expiry.Before(now) {
+			delete(revoked, tokenID)
+		}
+	}
+}
+
+// Returns revoked token IDs stored in the secret. Values hold expiration time, optionally followed by the time
+// the token is revoked from. Empty times mean that token never expires and that it is revoked immediately.
+func parseRevocations(secret *v1.Secret) map[string]revocation {
+	revoked := make(map[string]revocation)
+	for tokenID, data := range secret.Data {
+		times := strings.SplitN(string(data), revocationSeparator, 2)
+		expiry, err := parseRevocationTime(times[0])
+		if err != nil {
+			continue
+		}
+
+		from := time.Time{}
+		if len(times) > 1 {
+			if from, err = parseRevocationTime(times[1]); err != nil {
+				continue
+			}
+		}
+
+		revoked[tokenID] = revocation{from: from, expiry: expiry}
+	}
+
+	return revoked
+}
+
+func parseRevocationTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(timeFormat, value)
+}
+
+func formatRevocation(entry revocation) string {
+	value := formatRevocationTime(entry.expiry)
+	if !entry.from.IsZero() {
+		value += revocationSeparator + formatRevocationTime(entry.from)
+	}
+
+	return value
+}
+
+func formatRevocationTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.Format(timeFormat)
+}
+
+// NewRevocationList creates RevocationList that keeps revoked token IDs only in memory.
+func NewRevocationList() RevocationList {
+	return &revocationList{revoked: make(map[string]revocation)}
+}
+
+// NewSecretRevocationList creates RevocationList that stores revoked token IDs in a secret and keeps
+// them in sync with it using given client.
+func NewSecretRevocationList(client kubernetes.Interface) RevocationList {
+	list := &revocationList{
+		client:    client,
+		name:      authApi.TokenRevocationListName,
+		namespace: authApi.EncryptionKeyHolderNamespace,
+		revoked:   make(map[string]revocation),
+	}
+
+	list.init()
+	return list
+}
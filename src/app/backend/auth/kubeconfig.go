@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sort"
+	"strings"
+)
+
+// Implements Authenticator interface.
+type kubeConfigAuthenticator struct {
+	fileContent []byte
+	context     string
+}
+
+// GetAuthInfo implements Authenticator interface. It parses provided kubeconfig file and extracts auth info
+// of the selected context. If no context has been selected then current context of kubeconfig is used.
+func (self *kubeConfigAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	kubeConfig, err := clientcmd.Load(self.fileContent)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	contextName := self.context
+	if len(contextName) == 0 {
+		contextName = kubeConfig.CurrentContext
+	}
+
+	if len(contextName) == 0 {
+		return api.AuthInfo{}, errors.New("No context selected and kubeconfig has no current context set.")
+	}
+
+	context, exists := kubeConfig.Contexts[contextName]
+	if !exists {
+		return api.AuthInfo{}, fmt.Errorf("Context %s not found in kubeconfig. Available contexts: %s.",
+			contextName, strings.Join(self.contextNames(kubeConfig), ", "))
+	}
+
+	authInfo, exists := kubeConfig.AuthInfos[context.AuthInfo]
+	if !exists {
+		return api.AuthInfo{}, fmt.Errorf("User %s referenced by context %s not found in kubeconfig.",
+			context.AuthInfo, contextName)
+	}
+
+	return self.getAuthInfo(authInfo)
+}
+
+// Returns auth info that contains only supported credentials. Files referenced by the kubeconfig are stored
+// on user machine, so only embedded data can be used.
+func (self *kubeConfigAuthenticator) getAuthInfo(info *api.AuthInfo) (api.AuthInfo, error) {
+	switch {
+	case len(info.Token) > 0:
+		return api.AuthInfo{Token: info.Token}, nil
+	case len(info.ClientCertificateData) > 0 && len(info.ClientKeyData) > 0:
+		return api.AuthInfo{
+			ClientCertificateData: info.ClientCertificateData,
+			ClientKeyData:         info.ClientKeyData,
+		}, nil
+	case len(info.Username) > 0 && len(info.Password) > 0:
+		return api.AuthInfo{Username: info.Username, Password: info.Password}, nil
+	case len(info.TokenFile) > 0 || len(info.ClientCertificate) > 0 || len(info.ClientKey) > 0:
+		return api.AuthInfo{}, errors.New("Kubeconfig references credential files. Only embedded token, " +
+			"client certificate data or basic credentials are supported.")
+	}
+
+	return api.AuthInfo{}, errors.New("Not enough data to create auth info structure.")
+}
+
+func (self *kubeConfigAuthenticator) contextNames(kubeConfig *api.Config) []string {
+	names := make([]string, 0, len(kubeConfig.Contexts))
+	for name := range kubeConfig.Contexts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// NewKubeConfigAuthenticator returns Authenticator based on LoginSpec.
+func NewKubeConfigAuthenticator(spec *authApi.LoginSpec) authApi.Authenticator {
+	return &kubeConfigAuthenticator{
+		fileContent: []byte(spec.KubeConfig),
+		context:     spec.Context,
+	}
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"reflect"
+	"testing"
+)
+
+const testKubeConfig = `
+apiVersion: v1
+kind: Config
+current-context: token-context
+clusters:
+- name: test-cluster
+  cluster:
+    server: https://localhost:6443
+contexts:
+- name: token-context
+  context:
+    cluster: test-cluster
+    user: token-user
+- name: basic-context
+  context:
+    cluster: test-cluster
+    user: basic-user
+- name: file-context
+  context:
+    cluster: test-cluster
+    user: file-user
+users:
+- name: token-user
+  user:
+    token: test-token
+- name: basic-user
+  user:
+    username: test-user
+    password: test-password
+- name: file-user
+  user:
+    client-certificate: /home/user/.kube/client.crt
+    client-key: /home/user/.kube/client.key
+`
+
+func TestKubeConfigAuthenticator_GetAuthInfo(t *testing.T) {
+	cases := []struct {
+		info        string
+		context     string
+		expected    api.AuthInfo
+		expectedErr bool
+	}{
+		{"Should use current context", "", api.AuthInfo{Token: "test-token"}, false},
+		{"Should use selected context", "basic-context",
+			api.AuthInfo{Username: "test-user", Password: "test-password"}, false},
+		{"Should reject credential files", "file-context", api.AuthInfo{}, true},
+		{"Should reject unknown context", "unknown-context", api.AuthInfo{}, true},
+	}
+
+	for _, c := range cases {
+		authenticator := NewKubeConfigAuthenticator(&authApi.LoginSpec{
+			KubeConfig: testKubeConfig,
+			Context:    c.context,
+		})
+		authInfo, err := authenticator.GetAuthInfo()
+
+		if (err != nil) != c.expectedErr {
+			t.Errorf("Test case: %s. Expected error: %t, but got %v.", c.info, c.expectedErr, err)
+		}
+
+		if !reflect.DeepEqual(authInfo, c.expected) {
+			t.Errorf("Test case: %s. Expected: %v, but got %v.", c.info, c.expected, authInfo)
+		}
+	}
+}
@@ -3,9 +3,11 @@ package auth
 import (
 	"errors"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"github.com/wzt3309/k8sconsole/src/app/backend/auth/oidc"
 	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
 	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
 	"k8s.io/client-go/tools/clientcmd/api"
+	"time"
 )
 
 // Implements AuthManager interface
@@ -14,6 +16,8 @@ type authManager struct {
 	clientManager             	clientApi.ClientManager
 	authenticationModes       	authApi.AuthenticationModes
 	authenticationSkippable 		bool
+	// OpenID Connect issuer used by 'oidc' authentication mode. Nil if the mode is not configured.
+	oidcProvider *oidc.Provider
 }
 
 // Login implement AuthManager
@@ -28,13 +32,13 @@ func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, e
 		return nil, err
 	}
 
-	err = self.healthCheck(authInfo)
+	err = self.healthCheck(spec.Cluster, authInfo)
 	nonCriticalErrors, criticalError := kcErrors.HandleError(err)
 	if criticalError != nil || len(nonCriticalErrors) > 0 {
 		return &authApi.AuthResponse{Errors: nonCriticalErrors}, criticalError
 	}
 
-	token, err := self.tokenManager.Generate(authInfo)
+	token, err := self.generateToken(spec, authInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +46,61 @@ func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, e
 	return &authApi.AuthResponse{JWEToken: token, Errors: nonCriticalErrors}, nil
 }
 
-// Refresh implements AuthManager
+// Refresh implements AuthManager. In case token contains OpenID Connect ID token that is about to expire,
+// the ID token is refreshed as well.
 func (self authManager) Refresh(jweToken string) (string, error) {
-	return self.tokenManager.Refresh(jweToken)
+	if len(jweToken) == 0 || self.oidcProvider == nil {
+		return self.tokenManager.Refresh(jweToken)
+	}
+
+	authInfo, clusters, err := self.tokenManager.DecryptWithClusters(jweToken)
+	if err != nil {
+		return "", err
+	}
+
+	if !isOIDCAuthInfo(authInfo) {
+		return self.tokenManager.Refresh(jweToken)
+	}
+
+	refreshedAuthInfo, err := refreshOIDCAuthInfo(self.oidcProvider, authInfo, self.oidcRefreshMargin())
+	if err != nil {
+		return "", err
+	}
+
+	refreshedToken, err := self.tokenManager.GenerateWithClusters(refreshedAuthInfo, clusters)
+	if err != nil {
+		return "", err
+	}
+
+	if err = self.tokenManager.RevokeRefreshed(jweToken); err != nil {
+		return "", err
+	}
+
+	return refreshedToken, nil
+}
+
+// Logout implements AuthManager
+func (self authManager) Logout(jweToken string) error {
+	return self.tokenManager.Revoke(jweToken)
+}
+
+// OIDCAuthURL implements AuthManager
+func (self authManager) OIDCAuthURL(nonce string) (string, error) {
+	if self.oidcProvider == nil || !self.authenticationModes.IsEnabled(authApi.OIDC) {
+		return "", errors.New("OIDC authentication mode is not enabled.")
+	}
+
+	return self.oidcProvider.AuthCodeURL(newOIDCState(self.clientManager.CSRFKey(), nonce))
+}
+
+// Returns period before expiry of ID token in which it is refreshed, so it stays valid for the whole lifetime
+// of refreshed JWE token. Tokens generated without TTL never expire, so default TTL is used for them.
+func (self authManager) oidcRefreshMargin() time.Duration {
+	if ttl := self.tokenManager.TokenTTL(); ttl > 0 {
+		return ttl
+	}
+
+	return authApi.DefaultTokenTTL * time.Second
 }
 
 // AuthenticationModes implements AuthManager
@@ -67,23 +123,56 @@ func (self authManager) getAuthenticator(spec *authApi.LoginSpec) (authApi.Authe
 		return NewBasicAuthenticator(spec), nil
 	case len(spec.Token) > 0 && self.authenticationModes.IsEnabled(authApi.Token):
 		return NewTokenAuthenticator(spec), nil
+	case len(spec.KubeConfig) > 0 && self.authenticationModes.IsEnabled(authApi.KubeConfig):
+		return NewKubeConfigAuthenticator(spec), nil
+	case len(spec.OIDCCode) > 0 && self.oidcProvider != nil && self.authenticationModes.IsEnabled(authApi.OIDC):
+		return NewOIDCAuthenticator(spec, self.oidcProvider, self.clientManager.CSRFKey()), nil
 	}
 
 	return nil, errors.New("No enough data to create supported authenticator.")
 }
 
+// Generates token with auth info used to access the cluster user logs in to. Auth infos of other clusters
+// are copied from the token provided in login spec, so it has to be still valid.
+func (self authManager) generateToken(spec *authApi.LoginSpec, authInfo api.AuthInfo) (string, error) {
+	if len(spec.JWEToken) == 0 {
+		if len(spec.Cluster) == 0 {
+			return self.tokenManager.Generate(authInfo)
+		}
+
+		return self.tokenManager.GenerateWithClusters(api.AuthInfo{},
+			map[string]api.AuthInfo{spec.Cluster: authInfo})
+	}
+
+	defaultAuthInfo, clusters, err := self.tokenManager.DecryptWithClusters(spec.JWEToken)
+	if err != nil {
+		return "", err
+	}
+
+	if len(spec.Cluster) == 0 {
+		return self.tokenManager.GenerateWithClusters(authInfo, clusters)
+	}
+
+	clusters[spec.Cluster] = authInfo
+	return self.tokenManager.GenerateWithClusters(*defaultAuthInfo, clusters)
+}
+
 // Checks if user data extracted from provided AuthInfo is valid and user is correctly authenticated
-// by k8s apiserver
-func (self authManager) healthCheck(authInfo api.AuthInfo) error {
-	return self.clientManager.HasAccess(authInfo)
+// by k8s apiserver of given cluster
+func (self authManager) healthCheck(cluster string, authInfo api.AuthInfo) error {
+	return self.clientManager.HasClusterAccess(cluster, authInfo)
 }
 
+// NewAuthManager creates AuthManager instance. OIDC provider can be nil if 'oidc' authentication mode
+// is not configured.
 func NewAuthManager(clientManager clientApi.ClientManager, tokenManager authApi.TokenManager,
-	authenticationModes authApi.AuthenticationModes, authenticationSkippable bool) authApi.AuthManager {
+	authenticationModes authApi.AuthenticationModes, authenticationSkippable bool,
+	oidcProvider *oidc.Provider) authApi.AuthManager {
 	return &authManager{
 		tokenManager:            tokenManager,
 		clientManager:           clientManager,
 		authenticationModes:     authenticationModes,
 		authenticationSkippable: authenticationSkippable,
+		oidcProvider:            oidcProvider,
 	}
 }
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"github.com/wzt3309/k8sconsole/src/app/backend/auth/oidc"
+	"golang.org/x/net/xsrftoken"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"time"
+)
+
+const (
+	// Action used to generate and validate state parameter of OIDC authorization request.
+	oidcStateAction = "oidc"
+
+	// Name of cookie that holds nonce the state has been generated for, so the state is valid only in browser
+	// that has been redirected to the issuer.
+	OIDCNonceCookie = "k8sconsole-oidc-nonce"
+
+	// Length (in bytes) of random nonce.
+	oidcNonceLength = 16
+)
+
+// Implements Authenticator interface.
+type oidcAuthenticator struct {
+	provider *oidc.Provider
+	csrfKey  string
+	code     string
+	state    string
+	nonce    string
+}
+
+// GetAuthInfo implements Authenticator interface. It exchanges authorization code returned by the issuer
+// for ID token that is used as bearer token. Refresh token is kept in auth provider config.
+func (self *oidcAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	if len(self.nonce) == 0 || !xsrftoken.Valid(self.state, self.csrfKey, self.nonce, oidcStateAction) {
+		return api.AuthInfo{}, errors.New("Invalid or expired OIDC state.")
+	}
+
+	tokens, err := self.provider.Exchange(self.code)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	return newOIDCAuthInfo(tokens), nil
+}
+
+// NewOIDCNonce returns random nonce that binds state of OIDC authorization request to the browser of the user.
+func NewOIDCNonce() (string, error) {
+	nonce := make([]byte, oidcNonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
+
+// Returns state that has to be passed to the issuer during authorization. It is valid only together with given
+// nonce.
+func newOIDCState(csrfKey, nonce string) string {
+	return xsrftoken.Generate(csrfKey, nonce, oidcStateAction)
+}
+
+func newOIDCAuthInfo(tokens *oidc.Tokens) api.AuthInfo {
+	return api.AuthInfo{
+		Token: tokens.IDToken,
+		AuthProvider: &api.AuthProviderConfig{
+			Name: authApi.OIDC.String(),
+			Config: map[string]string{
+				authApi.OIDCRefreshTokenKey: tokens.RefreshToken,
+				authApi.OIDCExpiryKey:       tokens.Expiry.Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+// Returns true if auth info has been created by OIDC authenticator.
+func isOIDCAuthInfo(authInfo *api.AuthInfo) bool {
+	return authInfo.AuthProvider != nil && authInfo.AuthProvider.Name == authApi.OIDC.String()
+}
+
+// Refreshes ID token stored in auth info if it expires within given margin. Otherwise the given auth info is
+// returned.
+func refreshOIDCAuthInfo(provider *oidc.Provider, authInfo *api.AuthInfo, margin time.Duration) (api.AuthInfo,
+	error) {
+	expiry, err := time.Parse(time.RFC3339, authInfo.AuthProvider.Config[authApi.OIDCExpiryKey])
+	if err == nil && time.Until(expiry) > margin {
+		return *authInfo, nil
+	}
+
+	tokens, err := provider.Refresh(authInfo.AuthProvider.Config[authApi.OIDCRefreshTokenKey])
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	return newOIDCAuthInfo(tokens), nil
+}
+
+// NewOIDCAuthenticator returns Authenticator based on LoginSpec.
+func NewOIDCAuthenticator(spec *authApi.LoginSpec, provider *oidc.Provider, csrfKey string) authApi.Authenticator {
+	return &oidcAuthenticator{
+		provider: provider,
+		csrfKey:  csrfKey,
+		code:     spec.OIDCCode,
+		state:    spec.OIDCState,
+		nonce:    spec.OIDCNonce,
+	}
+}
@@ -0,0 +1,360 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"gopkg.in/square/go-jose.v2"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Path of OpenID Connect discovery document relative to issuer URL.
+	discoveryPath = "/.well-known/openid-configuration"
+	// Timeout of requests sent to the issuer.
+	requestTimeout = 30 * time.Second
+)
+
+// Config contains information required to authenticate users with OpenID Connect issuer.
+type Config struct {
+	// IssuerURL is the URL of OpenID Connect issuer, i.e. 'https://accounts.example.com'.
+	IssuerURL string
+	// ClientID is the ID of k8sconsole client registered by the issuer. ID tokens have to be issued
+	// for this client.
+	ClientID string
+	// ClientSecret is the secret of k8sconsole client used to exchange authorization code.
+	ClientSecret string
+	// RedirectURL is the URL issuer redirects user to after authorization.
+	RedirectURL string
+	// Scopes requested during authorization. 'openid' scope is always requested.
+	Scopes []string
+}
+
+// Tokens contains tokens returned by the issuer.
+type Tokens struct {
+	// IDToken is verified ID token that can be used as bearer token to access apiserver.
+	IDToken string
+	// RefreshToken can be used to obtain new ID token. It can be empty if issuer does not support it.
+	RefreshToken string
+	// Expiry is the expiration time of ID token.
+	Expiry time.Time
+}
+
+// Provider is responsible for communication with OpenID Connect issuer: building authorization URL,
+// exchanging authorization code, refreshing and verifying ID tokens.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	discovery *discoveryDocument
+	keys      *jose.JSONWebKeySet
+	mux       sync.Mutex
+}
+
+// Endpoints of the issuer read from discovery document.
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Response of the token endpoint.
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Claims of ID token that are verified by k8sconsole.
+type claims struct {
+	Issuer   string   `json:"iss"`
+	Audience audience `json:"aud"`
+	Expiry   int64    `json:"exp"`
+}
+
+// ID token audience can be a single string or an array of strings.
+type audience []string
+
+func (self *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*self = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*self = audience(multiple)
+	return nil
+}
+
+func (self audience) contains(clientID string) bool {
+	for _, aud := range self {
+		if aud == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthCodeURL returns URL of the issuer authorization endpoint user should be redirected to.
+// State is passed back by the issuer together with authorization code.
+func (self *Provider) AuthCodeURL(state string) (string, error) {
+	discovery, err := self.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {self.config.ClientID},
+		"redirect_uri":  {self.config.RedirectURL},
+		"scope":         {strings.Join(self.scopes(), " ")},
+		"state":         {state},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange exchanges authorization code for tokens and verifies returned ID token.
+func (self *Provider) Exchange(code string) (*Tokens, error) {
+	return self.requestTokens(url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {self.config.RedirectURL},
+	})
+}
+
+// Refresh obtains new ID token using given refresh token. In case the issuer does not return new refresh
+// token the given one is kept.
+func (self *Provider) Refresh(refreshToken string) (*Tokens, error) {
+	if len(refreshToken) == 0 {
+		return nil, errors.New("Can not refresh ID token. No refresh token provided.")
+	}
+
+	tokens, err := self.requestTokens(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens.RefreshToken) == 0 {
+		tokens.RefreshToken = refreshToken
+	}
+
+	return tokens, nil
+}
+
+// Verify checks ID token signature, issuer, audience and expiration time. Returns expiration time of
+// the token.
+func (self *Provider) Verify(idToken string) (time.Time, error) {
+	jws, err := jose.ParseSigned(idToken)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(jws.Signatures) != 1 {
+		return time.Time{}, errors.New("ID token has to contain exactly one signature.")
+	}
+
+	payload, err := self.verifySignature(jws, jws.Signatures[0].Header.KeyID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tokenClaims := new(claims)
+	if err := json.Unmarshal(payload, tokenClaims); err != nil {
+		return time.Time{}, errors.New("ID token verification error. Could not unmarshal claims.")
+	}
+
+	if tokenClaims.Issuer != self.config.IssuerURL {
+		return time.Time{}, fmt.Errorf("ID token issued by %s, expected %s.", tokenClaims.Issuer,
+			self.config.IssuerURL)
+	}
+
+	if !tokenClaims.Audience.contains(self.config.ClientID) {
+		return time.Time{}, errors.New("ID token has not been issued for k8sconsole client.")
+	}
+
+	expiry := time.Unix(tokenClaims.Expiry, 0)
+	if time.Now().After(expiry) {
+		return time.Time{}, errors.New("ID token has expired.")
+	}
+
+	return expiry, nil
+}
+
+// Verifies signature with issuer key of given ID. Issuer keys are reloaded once if the key is unknown,
+// as issuer could have rotated its keys.
+func (self *Provider) verifySignature(jws *jose.JSONWebSignature, keyID string) ([]byte, error) {
+	keys, err := self.getKeys(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys.Key(keyID)) == 0 {
+		if keys, err = self.getKeys(true); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range keys.Key(keyID) {
+		if payload, err := jws.Verify(key); err == nil {
+			return payload, nil
+		}
+	}
+
+	return nil, errors.New("ID token verification error. Invalid signature.")
+}
+
+func (self *Provider) requestTokens(params url.Values) (*Tokens, error) {
+	discovery, err := self.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(self.config.ClientID), url.QueryEscape(self.config.ClientSecret))
+
+	response := new(tokenResponse)
+	if err := self.do(request, response); err != nil && len(response.Error) == 0 {
+		return nil, err
+	}
+
+	if len(response.Error) > 0 {
+		return nil, fmt.Errorf("OIDC token request failed: %s %s", response.Error, response.ErrorDescription)
+	}
+
+	if len(response.IDToken) == 0 {
+		return nil, errors.New("OIDC token response does not contain ID token.")
+	}
+
+	expiry, err := self.Verify(response.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{IDToken: response.IDToken, RefreshToken: response.RefreshToken, Expiry: expiry}, nil
+}
+
+func (self *Provider) getDiscovery() (*discoveryDocument, error) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if self.discovery != nil {
+		return self.discovery, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(self.config.IssuerURL, "/")+discoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery := new(discoveryDocument)
+	if err := self.do(request, discovery); err != nil {
+		return nil, err
+	}
+
+	if discovery.Issuer != self.config.IssuerURL {
+		return nil, fmt.Errorf("OIDC issuer URL %s does not match %s from discovery document.",
+			self.config.IssuerURL, discovery.Issuer)
+	}
+
+	glog.Infof("Using OIDC issuer %s", discovery.Issuer)
+	self.discovery = discovery
+	return discovery, nil
+}
+
+func (self *Provider) getKeys(reload bool) (*jose.JSONWebKeySet, error) {
+	discovery, err := self.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if self.keys != nil && !reload {
+		return self.keys, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := new(jose.JSONWebKeySet)
+	if err := self.do(request, keys); err != nil {
+		return nil, err
+	}
+
+	self.keys = keys
+	return keys, nil
+}
+
+// Sends request to the issuer and unmarshals JSON response into given object. Error is returned
+// if response status is not 200, but the response body is unmarshalled anyway.
+func (self *Provider) do(request *http.Request, into interface{}) error {
+	request.Header.Set("Accept", "application/json")
+	response, err := self.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	unmarshalErr := json.Unmarshal(body, into)
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("OIDC request to %s failed with status %d.", request.URL.String(), response.StatusCode)
+	}
+
+	return unmarshalErr
+}
+
+func (self *Provider) scopes() []string {
+	scopes := []string{"openid"}
+	for _, scope := range self.config.Scopes {
+		if scope != "openid" && len(scope) > 0 {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+// NewProvider creates OpenID Connect provider based on given config. Issuer discovery document is
+// loaded on first use.
+func NewProvider(config Config) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
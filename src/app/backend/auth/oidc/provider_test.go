@@ -0,0 +1,170 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"gopkg.in/square/go-jose.v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Mock OpenID Connect issuer serving discovery document, signing keys and token endpoint.
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	keyID  string
+}
+
+func (self *testIssuer) signIDToken(t *testing.T, audience string, expiry time.Time) string {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: self.key, KeyID: self.keyID},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"iss": self.server.URL,
+		"aud": audience,
+		"exp": expiry.Unix(),
+		"sub": "test-user",
+	})
+
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := jws.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &testIssuer{key: key, keyID: "test-key"}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discoveryDocument{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/auth",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			JWKSURI:               issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: issuer.keyID, Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch {
+		case r.Form.Get("grant_type") == "authorization_code" && r.Form.Get("code") == "test-code":
+			json.NewEncoder(w).Encode(tokenResponse{
+				IDToken:      issuer.signIDToken(t, "k8sconsole", time.Now().Add(time.Hour)),
+				RefreshToken: "test-refresh-token",
+			})
+		case r.Form.Get("grant_type") == "refresh_token" && r.Form.Get("refresh_token") == "test-refresh-token":
+			json.NewEncoder(w).Encode(tokenResponse{
+				IDToken: issuer.signIDToken(t, "k8sconsole", time.Now().Add(2*time.Hour)),
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_grant"})
+		}
+	})
+	issuer.server = httptest.NewServer(mux)
+
+	return issuer
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	provider := NewProvider(Config{IssuerURL: issuer.server.URL, ClientID: "k8sconsole",
+		RedirectURL: "https://k8sconsole/login", Scopes: []string{"openid", "email"}})
+
+	authURL, err := provider.AuthCodeURL("test-state")
+	if err != nil {
+		t.Fatalf("AuthCodeURL(): Unexpected error: %s", err)
+	}
+
+	for _, expected := range []string{issuer.server.URL + "/auth?", "client_id=k8sconsole", "state=test-state",
+		"scope=openid+email", "response_type=code"} {
+		if !strings.Contains(authURL, expected) {
+			t.Errorf("AuthCodeURL(): Expected %s to contain %s", authURL, expected)
+		}
+	}
+}
+
+func TestProvider_ExchangeAndRefresh(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	provider := NewProvider(Config{IssuerURL: issuer.server.URL, ClientID: "k8sconsole"})
+
+	tokens, err := provider.Exchange("test-code")
+	if err != nil {
+		t.Fatalf("Exchange(): Unexpected error: %s", err)
+	}
+
+	if tokens.RefreshToken != "test-refresh-token" {
+		t.Errorf("Exchange(): Expected refresh token %s but got %s", "test-refresh-token", tokens.RefreshToken)
+	}
+
+	refreshed, err := provider.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh(): Unexpected error: %s", err)
+	}
+
+	if refreshed.IDToken == tokens.IDToken || !refreshed.Expiry.After(tokens.Expiry) {
+		t.Errorf("Refresh(): Expected new ID token with later expiry")
+	}
+
+	if refreshed.RefreshToken != tokens.RefreshToken {
+		t.Errorf("Refresh(): Expected refresh token to be kept but got %s", refreshed.RefreshToken)
+	}
+
+	if _, err := provider.Exchange("invalid-code"); err == nil {
+		t.Error("Exchange(): Expected error for invalid code")
+	}
+}
+
+func TestProvider_Verify(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	provider := NewProvider(Config{IssuerURL: issuer.server.URL, ClientID: "k8sconsole"})
+
+	cases := []struct {
+		info        string
+		token       string
+		expectedErr bool
+	}{
+		{"valid token", issuer.signIDToken(t, "k8sconsole", time.Now().Add(time.Hour)), false},
+		{"foreign audience", issuer.signIDToken(t, "other-client", time.Now().Add(time.Hour)), true},
+		{"expired token", issuer.signIDToken(t, "k8sconsole", time.Now().Add(-time.Hour)), true},
+		{"malformed token", "not-a-token", true},
+	}
+
+	for _, c := range cases {
+		_, err := provider.Verify(c.token)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("Verify() %s: Expected error to be %t but got %v", c.info, c.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"testing"
+)
+
+func TestOIDCAuthenticator_State(t *testing.T) {
+	nonce, err := NewOIDCNonce()
+	if err != nil {
+		t.Fatalf("NewOIDCNonce(): Unexpected error: %s", err)
+	}
+
+	otherNonce, _ := NewOIDCNonce()
+	state := newOIDCState("test-key", nonce)
+	cases := []struct {
+		info  string
+		nonce string
+	}{
+		{"Should reject state without nonce cookie", ""},
+		{"Should reject state generated for other browser", otherNonce},
+	}
+
+	for _, c := range cases {
+		spec := &authApi.LoginSpec{OIDCCode: "code", OIDCState: state, OIDCNonce: c.nonce}
+		if _, err := NewOIDCAuthenticator(spec, nil, "test-key").GetAuthInfo(); err == nil {
+			t.Errorf("Test case: %s. Expected error but got nil.", c.info)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Package bulk performs the same action on all objects of a kind selected by label and field selectors.
+package bulk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Actions that can be performed on selected objects.
+const (
+	ActionDelete   = "delete"
+	ActionLabel    = "label"
+	ActionAnnotate = "annotate"
+	ActionScale    = "scale"
+	ActionRestart  = "restart"
+)
+
+// Number of objects changed at the same time if the spec does not set it, and the maximal allowed number.
+const (
+	defaultConcurrency = 5
+	maxConcurrency     = 20
+)
+
+// Pod template annotation changed to restart pods of workload. It is the same annotation kubectl uses, so
+// rollout history shows restarts made by both tools the same way.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// Spec describes the action and objects it is performed on.
+type Spec struct {
+	// Kind of selected objects, in the same form as in raw resource routes, i.e. 'deployment'.
+	Kind string `json:"kind"`
+	// Namespaces objects are selected from. All namespaces are used if it is empty. It is ignored for cluster
+	// scoped kinds.
+	Namespaces []string `json:"namespaces"`
+	// LabelSelector and FieldSelector select objects the action is performed on. At least one of them has to be
+	// set, so all objects of a kind are never changed by mistake.
+	LabelSelector string `json:"labelSelector"`
+	FieldSelector string `json:"fieldSelector"`
+	Action        string `json:"action"`
+	// Labels set by label action. Null value removes the label.
+	Labels map[string]*string `json:"labels,omitempty"`
+	// Annotations set by annotate action. Null value removes the annotation.
+	Annotations map[string]*string `json:"annotations,omitempty"`
+	// Replicas set by scale action. Parallelism is set for jobs.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// PropagationPolicy used by delete action. Dependents are deleted in foreground if it is not set.
+	PropagationPolicy *metaV1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+	// Concurrency is the number of objects changed at the same time.
+	Concurrency int `json:"concurrency"`
+	// SelectionToken is returned by preview of the spec. It is required to perform the action, which fails if
+	// objects selected by the spec have changed since the preview.
+	SelectionToken string `json:"selectionToken"`
+}
+
+// ObjectResult describes outcome of the action performed on a single object.
+type ObjectResult struct {
+	Object clientApi.ObjectReference `json:"object"`
+	// Error is empty if the action has succeeded or, in preview, if it can be performed on the object.
+	Error string `json:"error,omitempty"`
+}
+
+// Result lists selected objects and outcome of the action performed on them.
+type Result struct {
+	Action string `json:"action"`
+	// Preview is true if the action has not been performed.
+	Preview   bool           `json:"preview"`
+	Items     []ObjectResult `json:"items"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	// SelectionToken identifies selected objects. It has to be passed in spec to perform the action.
+	SelectionToken string `json:"selectionToken"`
+}
+
+// Change of a single object.
+type operation func(verber clientApi.ResourceVerber) error
+
+// Preview returns objects selected by the spec without performing the action. Objects the action can not
+// be performed on, i.e. objects of kinds that can not be scaled, are reported as failed.
+func Preview(verber clientApi.ResourceVerber, spec *Spec) (*Result, error) {
+	return run(verber, spec, true)
+}
+
+// Perform performs the action on all objects selected by the spec. Failure of a single object does not stop
+// the action, it is reported in the result instead. Conflict error is returned if selected objects do not
+// match selection token of the spec.
+func Perform(verber clientApi.ResourceVerber, spec *Spec) (*Result, error) {
+	return run(verber, spec, false)
+}
+
+func run(verber clientApi.ResourceVerber, spec *Spec, preview bool) (*Result, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	objects, err := selectObjects(verber, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	token := selectionToken(objects)
+	if !preview && token != spec.SelectionToken {
+		return nil, k8sErrors.NewConflict(schema.GroupResource{Resource: spec.Kind}, "",
+			errors.New("selected objects have changed since the preview, preview the action again"))
+	}
+
+	result := &Result{Action: spec.Action, Preview: preview, Items: make([]ObjectResult, len(objects)),
+		SelectionToken: token}
+	concurrency := spec.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultConcurrency
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, object := range objects {
+		result.Items[i].Object = object
+		// Objects are selected by the spec instead of url, so they are not checked by restricted resources
+		// filter.
+		if authApi.ShouldRejectObject(object.Namespace, object.Name) {
+			result.Items[i].Error = "Access to the object is restricted"
+			continue
+		}
+
+		op, err := spec.operation(object)
+		if err != nil || preview {
+			result.Items[i].Error = errorString(err)
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(item *ObjectResult) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			item.Error = errorString(op(verber))
+		}(&result.Items[i])
+	}
+	wg.Wait()
+
+	for _, item := range result.Items {
+		if len(item.Error) > 0 {
+			result.Failed++
+		} else if !preview {
+			result.Succeeded++
+		}
+	}
+
+	return result, nil
+}
+
+func (self *Spec) validate() error {
+	switch {
+	case len(self.Kind) == 0:
+		return k8sErrors.NewBadRequest("Kind is required")
+	case len(self.LabelSelector) == 0 && len(self.FieldSelector) == 0:
+		return k8sErrors.NewBadRequest("Label or field selector is required")
+	case self.Concurrency < 0 || self.Concurrency > maxConcurrency:
+		return k8sErrors.NewBadRequest(fmt.Sprintf("Concurrency has to be between 0 and %d", maxConcurrency))
+	}
+
+	if _, err := labels.Parse(self.LabelSelector); err != nil {
+		return k8sErrors.NewBadRequest(fmt.Sprintf("Invalid label selector: %s", err))
+	}
+
+	if _, err := fields.ParseSelector(self.FieldSelector); err != nil {
+		return k8sErrors.NewBadRequest(fmt.Sprintf("Invalid field selector: %s", err))
+	}
+
+	switch self.Action {
+	case ActionDelete, ActionRestart:
+	case ActionLabel:
+		if len(self.Labels) == 0 {
+			return k8sErrors.NewBadRequest("Labels are required by label action")
+		}
+	case ActionAnnotate:
+		if len(self.Annotations) == 0 {
+			return k8sErrors.NewBadRequest("Annotations are required by annotate action")
+		}
+	case ActionScale:
+		if self.Replicas == nil || *self.Replicas < 0 {
+			return k8sErrors.NewBadRequest("Non-negative replicas are required by scale action")
+		}
+	default:
+		return k8sErrors.NewBadRequest(fmt.Sprintf("Unknown action %q", self.Action))
+	}
+
+	return nil
+}
+
+// Returns objects of the kind matching selectors from selected namespaces.
+func selectObjects(verber clientApi.ResourceVerber, spec *Spec) ([]clientApi.ObjectReference, error) {
+	nsQuery := common.NewNamespaceQuery(spec.Namespaces)
+	objects, err := verber.List(spec.Kind, nsQuery.ToRequestParam(), metaV1.ListOptions{
+		LabelSelector: spec.LabelSelector,
+		FieldSelector: spec.FieldSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]clientApi.ObjectReference, 0, len(objects))
+	for _, object := range objects {
+		// Cluster scoped objects have no namespace and are not filtered.
+		if len(object.Namespace) == 0 || nsQuery.Matches(object.Namespace) {
+			result = append(result, object)
+		}
+	}
+
+	return result, nil
+}
+
+// Returns hash of identities of given objects. Objects are sorted, so the token does not depend on the order
+// they are listed in.
+func selectionToken(objects []clientApi.ObjectReference) string {
+	ids := make([]string, len(objects))
+	for i, object := range objects {
+		ids[i] = object.Namespace + "/" + object.Name + "/" + string(object.UID)
+	}
+	sort.Strings(ids)
+
+	hash := sha256.New()
+	for _, id := range ids {
+		hash.Write([]byte(id + "\n"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Returns operation that performs the action on given object. Error is returned if the action can not be
+// performed on objects of its kind.
+func (self *Spec) operation(object clientApi.ObjectReference) (operation, error) {
+	switch self.Action {
+	case ActionDelete:
+		var options *metaV1.DeleteOptions
+		if self.PropagationPolicy != nil {
+			options = &metaV1.DeleteOptions{PropagationPolicy: self.PropagationPolicy}
+		}
+		return self.delete(object, options), nil
+	case ActionLabel:
+		return self.patch(object, map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": self.Labels},
+		}), nil
+	case ActionAnnotate:
+		return self.patch(object, map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": self.Annotations},
+		}), nil
+	case ActionScale:
+		return self.scale(object)
+	case ActionRestart:
+		return self.restart(object)
+	}
+
+	return nil, fmt.Errorf("Unknown action %q", self.Action)
+}
+
+func (self *Spec) scale(object clientApi.ObjectReference) (operation, error) {
+	switch object.Kind {
+	case "Deployment", "ReplicaSet", "ReplicationController", "StatefulSet":
+		return self.patch(object, map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": *self.Replicas},
+		}), nil
+	case "Job":
+		// Jobs are scaled by parallelism, the same way as by scale routes.
+		return self.patch(object, map[string]interface{}{
+			"spec": map[string]interface{}{"parallelism": *self.Replicas},
+		}), nil
+	}
+
+	return nil, fmt.Errorf("%s can not be scaled", object.Kind)
+}
+
+func (self *Spec) restart(object clientApi.ObjectReference) (operation, error) {
+	switch object.Kind {
+	case "Deployment", "DaemonSet", "StatefulSet":
+		return self.patch(object, map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]string{restartedAtAnnotation: time.Now().Format(time.RFC3339)},
+					},
+				},
+			},
+		}), nil
+	case "Pod":
+		// Pods are restarted by their controllers after they are deleted. Pods without a controller would be
+		// gone for good.
+		if len(object.Controller) == 0 {
+			return nil, errors.New("Pod is not managed by a controller and would not be created again")
+		}
+		return self.delete(object, nil), nil
+	}
+
+	return nil, fmt.Errorf("%s can not be restarted", object.Kind)
+}
+
+func (self *Spec) delete(object clientApi.ObjectReference, options *metaV1.DeleteOptions) operation {
+	return func(verber clientApi.ResourceVerber) error {
+		return verber.Delete(self.Kind, len(object.Namespace) > 0, object.Namespace, object.Name, options)
+	}
+}
+
+func (self *Spec) patch(object clientApi.ObjectReference, patch map[string]interface{}) operation {
+	return func(verber clientApi.ResourceVerber) error {
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return err
+		}
+
+		_, err = verber.Patch(self.Kind, len(object.Namespace) > 0, object.Namespace, object.Name,
+			types.MergePatchType, data)
+		return err
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,211 @@
+package bulk
+
+import (
+	"errors"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// Verber that lists given objects and records patches and deletes.
+type fakeVerber struct {
+	clientApi.ResourceVerber
+	objects []clientApi.ObjectReference
+	failing string
+	mux     sync.Mutex
+	calls   []string
+}
+
+func (self *fakeVerber) List(kind string, namespace string, options metaV1.ListOptions) (
+	[]clientApi.ObjectReference, error) {
+	return self.objects, nil
+}
+
+func (self *fakeVerber) Delete(kind string, namespaceSet bool, namespace string, name string,
+	options *metaV1.DeleteOptions) error {
+	return self.record("delete "+namespace+"/"+name, name)
+}
+
+func (self *fakeVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, patch []byte) (runtime.Object, error) {
+	return nil, self.record("patch "+namespace+"/"+name+" "+string(patch), name)
+}
+
+func (self *fakeVerber) record(call, name string) error {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.calls = append(self.calls, call)
+	if name == self.failing {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func TestPerform(t *testing.T) {
+	verber := &fakeVerber{
+		objects: []clientApi.ObjectReference{
+			{Kind: "Pod", Namespace: "test", Name: "a"},
+			{Kind: "Pod", Namespace: "test", Name: "b"},
+			{Kind: "Pod", Namespace: "other", Name: "c"},
+		},
+		failing: "b",
+	}
+	spec := &Spec{Kind: "pod", Namespaces: []string{"test", "prod"}, LabelSelector: "tier=fe",
+		Action: ActionDelete}
+
+	preview, err := Preview(verber, spec)
+	if err != nil {
+		t.Fatalf("Preview(): Unexpected error: %s", err)
+	}
+
+	if !preview.Preview || len(preview.Items) != 2 || len(verber.calls) != 0 {
+		t.Errorf("Preview(): Expected two objects from selected namespaces and no calls, got %+v %v", preview,
+			verber.calls)
+	}
+
+	if _, err := Perform(verber, spec); !k8sErrors.IsConflict(err) {
+		t.Errorf("Perform(): Expected conflict without selection token but got %v", err)
+	}
+
+	spec.SelectionToken = preview.SelectionToken
+	result, err := Perform(verber, spec)
+	if err != nil {
+		t.Fatalf("Perform(): Unexpected error: %s", err)
+	}
+
+	sort.Strings(verber.calls)
+	if expected := []string{"delete test/a", "delete test/b"}; !reflect.DeepEqual(verber.calls, expected) {
+		t.Errorf("Perform(): Expected calls %v but got %v", expected, verber.calls)
+	}
+
+	if result.Succeeded != 1 || result.Failed != 1 || result.Items[1].Error != "failed" {
+		t.Errorf("Perform(): Expected failure of b to be reported but got %+v", result)
+	}
+}
+
+func TestPreview_UnsupportedKind(t *testing.T) {
+	replicas := int32(2)
+	verber := &fakeVerber{objects: []clientApi.ObjectReference{{Kind: "ConfigMap", Namespace: "test", Name: "a"}}}
+	spec := &Spec{Kind: "configmap", LabelSelector: "app", Action: ActionScale, Replicas: &replicas}
+
+	result, err := Preview(verber, spec)
+	if err != nil {
+		t.Fatalf("Preview(): Unexpected error: %s", err)
+	}
+
+	if result.Failed != 1 || result.Items[0].Error != "ConfigMap can not be scaled" {
+		t.Errorf("Preview(): Expected ConfigMap not to be scalable but got %+v", result)
+	}
+}
+
+func TestPerform_Scale(t *testing.T) {
+	replicas := int32(0)
+	verber := &fakeVerber{objects: []clientApi.ObjectReference{
+		{Kind: "Deployment", Namespace: "test", Name: "web"},
+		{Kind: "Job", Namespace: "test", Name: "batch"},
+	}}
+	spec := &Spec{Kind: "deployment", LabelSelector: "app", Action: ActionScale, Replicas: &replicas,
+		SelectionToken: selectionToken(verber.objects)}
+
+	if _, err := Perform(verber, spec); err != nil {
+		t.Fatalf("Perform(): Unexpected error: %s", err)
+	}
+
+	sort.Strings(verber.calls)
+	expected := []string{`patch test/batch {"spec":{"parallelism":0}}`, `patch test/web {"spec":{"replicas":0}}`}
+	if !reflect.DeepEqual(verber.calls, expected) {
+		t.Errorf("Perform(): Expected calls %v but got %v", expected, verber.calls)
+	}
+}
+
+func TestPerform_RestartPod(t *testing.T) {
+	verber := &fakeVerber{objects: []clientApi.ObjectReference{
+		{Kind: "Pod", Namespace: "test", Name: "managed", Owners: []types.UID{"1"}, Controller: "1"},
+		{Kind: "Pod", Namespace: "test", Name: "bare"},
+	}}
+	spec := &Spec{Kind: "pod", LabelSelector: "app", Action: ActionRestart}
+
+	preview, err := Preview(verber, spec)
+	if err != nil {
+		t.Fatalf("Preview(): Unexpected error: %s", err)
+	}
+
+	if preview.Failed != 1 || len(preview.Items[0].Error) > 0 || len(preview.Items[1].Error) == 0 {
+		t.Errorf("Preview(): Expected pod without controller to be reported as failed but got %+v", preview)
+	}
+
+	spec.SelectionToken = preview.SelectionToken
+	if _, err := Perform(verber, spec); err != nil {
+		t.Fatalf("Perform(): Unexpected error: %s", err)
+	}
+
+	if expected := []string{"delete test/managed"}; !reflect.DeepEqual(verber.calls, expected) {
+		t.Errorf("Perform(): Expected calls %v but got %v", expected, verber.calls)
+	}
+}
+
+func TestPerform_SelectionChanged(t *testing.T) {
+	verber := &fakeVerber{objects: []clientApi.ObjectReference{{Kind: "Pod", Namespace: "test", Name: "a"}}}
+	spec := &Spec{Kind: "pod", LabelSelector: "app", Action: ActionDelete}
+
+	preview, err := Preview(verber, spec)
+	if err != nil {
+		t.Fatalf("Preview(): Unexpected error: %s", err)
+	}
+
+	verber.objects = append(verber.objects, clientApi.ObjectReference{Kind: "Pod", Namespace: "test", Name: "b"})
+	spec.SelectionToken = preview.SelectionToken
+	if _, err := Perform(verber, spec); !k8sErrors.IsConflict(err) {
+		t.Errorf("Perform(): Expected conflict after selection has changed but got %v", err)
+	}
+
+	if len(verber.calls) != 0 {
+		t.Errorf("Perform(): Expected no calls but got %v", verber.calls)
+	}
+}
+
+func TestPerform_RestrictedObject(t *testing.T) {
+	verber := &fakeVerber{objects: []clientApi.ObjectReference{
+		{Kind: "Secret", Namespace: "kube-system", Name: "k8sconsole-key-holder"},
+		{Kind: "Secret", Namespace: "kube-system", Name: "token"},
+	}}
+	spec := &Spec{Kind: "secret", FieldSelector: "metadata.namespace=kube-system", Action: ActionDelete,
+		SelectionToken: selectionToken(verber.objects)}
+
+	result, err := Perform(verber, spec)
+	if err != nil {
+		t.Fatalf("Perform(): Unexpected error: %s", err)
+	}
+
+	if expected := []string{"delete kube-system/token"}; !reflect.DeepEqual(verber.calls, expected) {
+		t.Errorf("Perform(): Expected calls %v but got %v", expected, verber.calls)
+	}
+
+	if result.Failed != 1 || len(result.Items[0].Error) == 0 {
+		t.Errorf("Perform(): Expected restricted object to be reported as failed but got %+v", result)
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	cases := []Spec{
+		{LabelSelector: "app", Action: ActionDelete},
+		{Kind: "pod", Action: ActionDelete},
+		{Kind: "pod", LabelSelector: "app in (", Action: ActionDelete},
+		{Kind: "pod", LabelSelector: "app", Action: ActionDelete, Concurrency: maxConcurrency + 1},
+		{Kind: "pod", LabelSelector: "app", Action: ActionLabel},
+		{Kind: "pod", LabelSelector: "app", Action: ActionScale},
+		{Kind: "pod", LabelSelector: "app", Action: "unknown"},
+	}
+
+	for _, c := range cases {
+		if err := c.validate(); !k8sErrors.IsBadRequest(err) {
+			t.Errorf("validate(%+v): Expected bad request but got %v", c, err)
+		}
+	}
+}
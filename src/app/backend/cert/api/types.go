@@ -0,0 +1,36 @@
+package api
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+const (
+	// The name of secret that stores auto-generated certificates.
+	CertHolderName      = "k8sconsole-certs"
+	CertHolderNamespace = "kube-system"
+
+	// Host name used as common name of auto-generated self-signed certificate.
+	DefaultCertHost = "k8sconsole"
+
+	// Interval between checks whether the certificate source has changed.
+	DefaultReloadPeriod = 30 * time.Second
+)
+
+// Manager is responsible for loading certificates used to serve k8sconsole over HTTPS and for keeping them
+// in sync with their source, so certificates can be rotated without restarting k8sconsole.
+type Manager interface {
+	// GetCertificate returns current certificate. It can be used as GetCertificate callback of tls.Config.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	// Reload forces reload of the certificate from its source.
+	Reload() error
+	// Run periodically reloads the certificate until stop channel is closed.
+	Run(period time.Duration, stopCh <-chan struct{})
+}
+
+// Source represents the storage of PEM encoded certificate and private key, i.e. files on the disk or
+// k8s secret.
+type Source interface {
+	// Load returns PEM encoded certificate and private key.
+	Load() (certPEM []byte, keyPEM []byte, err error)
+}
@@ -0,0 +1,33 @@
+package cert
+
+import (
+	certApi "github.com/wzt3309/k8sconsole/src/app/backend/cert/api"
+	"io/ioutil"
+)
+
+// Implements Source interface. Reads certificate and key from files provided with '--tls-cert-file' and
+// '--tls-key-file' arguments.
+type fileSource struct {
+	certFile string
+	keyFile  string
+}
+
+// Load implements Source interface.
+func (self *fileSource) Load() ([]byte, []byte, error) {
+	certPEM, err := ioutil.ReadFile(self.certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := ioutil.ReadFile(self.keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certPEM, keyPEM, nil
+}
+
+// NewFileSource creates Source that reads certificate and key from the given files.
+func NewFileSource(certFile, keyFile string) certApi.Source {
+	return &fileSource{certFile: certFile, keyFile: keyFile}
+}
@@ -0,0 +1,88 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"github.com/golang/glog"
+	certApi "github.com/wzt3309/k8sconsole/src/app/backend/cert/api"
+	"sync"
+	"time"
+)
+
+// Implements Manager interface.
+type certManager struct {
+	source certApi.Source
+
+	certPEM []byte
+	keyPEM  []byte
+	cert    *tls.Certificate
+	mux     sync.RWMutex
+}
+
+// GetCertificate implements Manager interface.
+func (self *certManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	self.mux.RLock()
+	defer self.mux.RUnlock()
+
+	if self.cert == nil {
+		return nil, errors.New("No certificate loaded.")
+	}
+
+	return self.cert, nil
+}
+
+// Reload implements Manager interface. Certificate is replaced only if its source has changed.
+func (self *certManager) Reload() error {
+	certPEM, keyPEM, err := self.source.Load()
+	if err != nil {
+		return err
+	}
+
+	self.mux.RLock()
+	unchanged := bytes.Equal(certPEM, self.certPEM) && bytes.Equal(keyPEM, self.keyPEM)
+	self.mux.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.certPEM = certPEM
+	self.keyPEM = keyPEM
+	self.cert = &cert
+	glog.Info("Loaded new TLS certificate")
+	return nil
+}
+
+// Run implements Manager interface.
+func (self *certManager) Run(period time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := self.Reload(); err != nil {
+				glog.Errorf("Could not reload TLS certificate: %s", err.Error())
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// NewCertManager creates Manager instance and loads the certificate from given source.
+func NewCertManager(source certApi.Source) (certApi.Manager, error) {
+	manager := &certManager{source: source}
+	if err := manager.Reload(); err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
@@ -0,0 +1,79 @@
+package cert
+
+import (
+	"io/ioutil"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/cert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCertFiles(t *testing.T, dir, host string) (string, string) {
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(host, nil, nil)
+	if err != nil {
+		t.Fatalf("Could not generate certificate: %s", err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+func TestCertManager_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "k8sconsole-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeCertFiles(t, dir, "first")
+	manager, err := NewCertManager(NewFileSource(certFile, keyFile))
+	if err != nil {
+		t.Fatalf("NewCertManager(): Expected certificate to be loaded but error was thrown: %s", err)
+	}
+
+	first, _ := manager.GetCertificate(nil)
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload(): Unexpected error: %s", err)
+	}
+
+	if unchanged, _ := manager.GetCertificate(nil); unchanged != first {
+		t.Error("Reload(): Expected certificate not to be replaced when files have not changed")
+	}
+
+	writeCertFiles(t, dir, "second")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload(): Unexpected error: %s", err)
+	}
+
+	if reloaded, _ := manager.GetCertificate(nil); reloaded == first {
+		t.Error("Reload(): Expected certificate to be replaced after files have changed")
+	}
+}
+
+func TestSecretSource_Load(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	source := NewSecretSource(client)
+
+	certPEM, keyPEM, err := source.Load()
+	if err != nil {
+		t.Fatalf("Load(): Expected certificate to be generated but error was thrown: %s", err)
+	}
+
+	reloadedCertPEM, reloadedKeyPEM, err := source.Load()
+	if err != nil {
+		t.Fatalf("Load(): Unexpected error: %s", err)
+	}
+
+	if string(certPEM) != string(reloadedCertPEM) || string(keyPEM) != string(reloadedKeyPEM) {
+		t.Error("Load(): Expected certificate stored in secret to be reused")
+	}
+}
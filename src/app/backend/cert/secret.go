@@ -0,0 +1,79 @@
+package cert
+
+import (
+	"errors"
+	"github.com/golang/glog"
+	certApi "github.com/wzt3309/k8sconsole/src/app/backend/cert/api"
+	"k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+)
+
+// Implements Source interface. Stores self-signed certificate in k8s secret, so all k8sconsole replicas
+// serve the same certificate. Certificate is generated only when the secret does not exist yet.
+type secretSource struct {
+	client    kubernetes.Interface
+	name      string
+	namespace string
+	host      string
+}
+
+// Load implements Source interface.
+func (self *secretSource) Load() ([]byte, []byte, error) {
+	secret, err := self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		secret, err = self.create()
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM, keyPEM := secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey]
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, nil, errors.New("Certificate secret does not contain certificate or private key.")
+	}
+
+	return certPEM, keyPEM, nil
+}
+
+// Generates self-signed certificate and stores it in a secret. In case other replica has created the secret
+// in the meantime the existing one is returned.
+func (self *secretSource) create() (*v1.Secret, error) {
+	glog.Infof("Generating self-signed certificate for %s", self.host)
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(self.host, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      self.name,
+			Namespace: self.namespace,
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       certPEM,
+			v1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+
+	created, err := self.client.CoreV1().Secrets(self.namespace).Create(secret)
+	if k8sErrors.IsAlreadyExists(err) {
+		return self.client.CoreV1().Secrets(self.namespace).Get(self.name, metaV1.GetOptions{})
+	}
+
+	return created, err
+}
+
+// NewSecretSource creates Source that keeps auto-generated self-signed certificate in k8s secret.
+func NewSecretSource(client kubernetes.Interface) certApi.Source {
+	return &secretSource{
+		client:    client,
+		name:      certApi.CertHolderName,
+		namespace: certApi.CertHolderNamespace,
+		host:      certApi.DefaultCertHost,
+	}
+}
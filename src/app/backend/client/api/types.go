@@ -1,35 +1,109 @@
 package api
 
 import (
+	"crypto/x509"
 	"github.com/emicklei/go-restful"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"github.com/wzt3309/k8sconsole/src/app/backend/dryrun"
 	"k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+	"net"
 )
 
+// ClusterHeader is the name of request header that contains name of the cluster request is sent to. Requests
+// without the header are sent to the default cluster.
+const ClusterHeader = "X-K8SCONSOLE-CLUSTER"
+
 // ClientManager is responsible for initializing and creating clients to communicate with
 // k8s apiserver.
 type ClientManager interface {
 	Client(*restful.Request) (kubernetes.Interface, error)
 	InsecureClient() kubernetes.Interface
+	InsecureClusterClient(*restful.Request) (kubernetes.Interface, error)
 	CanI(*restful.Request, *v1.SelfSubjectAccessReview) bool
 	Config(*restful.Request) (*rest.Config, error)
 	ClientCmdConfig(*restful.Request) (clientcmd.ClientConfig, error)
 	CSRFKey() string
 	HasAccess(api.AuthInfo) error
+	HasClusterAccess(string, api.AuthInfo) error
+	Clusters() []string
+	SetClusters(map[string]*rest.Config)
 	VerberClient(*restful.Request) (ResourceVerber, error)
 	SetTokenManager(manager authApi.TokenManager)
+	SetAuthProxyConfig(config *AuthProxyConfig)
+}
+
+// AuthProxyConfig describes authenticating proxy placed in front of k8sconsole. User identity passed by
+// the proxy in request headers is impersonated on apiserver with permissions of k8sconsole service account.
+// Headers are accepted only from requests coming from trusted networks or presenting client certificate
+// signed by trusted CA.
+type AuthProxyConfig struct {
+	// UserHeader is the name of header that contains username, i.e. 'X-Remote-User'.
+	UserHeader string
+	// GroupHeader is the name of header that contains user groups, i.e. 'X-Remote-Group'. It can be
+	// repeated or contain comma separated list of groups.
+	GroupHeader string
+	// TrustedCIDRs are networks requests with identity headers are accepted from.
+	TrustedCIDRs []*net.IPNet
+	// ClientCA is used to verify client certificate of the proxy. Can be nil.
+	ClientCA *x509.CertPool
 }
 
 // ResourceVerber is responsible for performing generic CRUD operations on all supported resources.
 type ResourceVerber interface {
+	Create(kind string, namespaceSet bool, namespace string, object *runtime.Unknown) (runtime.Object, error)
 	Put(kind string, namespaceSet bool, namespace string, name string, object *runtime.Unknown) error
+	PutDryRun(kind string, namespaceSet bool, namespace string, name string,
+		object *runtime.Unknown) (*dryrun.Result, error)
 	Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object, error)
-	Delete(kind string, namespaceSet bool, namespace string, name string) error
+	List(kind string, namespace string, options metaV1.ListOptions) ([]ObjectReference, error)
+	Delete(kind string, namespaceSet bool, namespace string, name string, options *metaV1.DeleteOptions) error
+	Patch(kind string, namespaceSet bool, namespace string, name string, patchType types.PatchType,
+		patch []byte) (runtime.Object, error)
+	PatchDryRun(kind string, namespaceSet bool, namespace string, name string, patchType types.PatchType,
+		patch []byte) (*dryrun.Result, error)
+	DeletePreview(kind string, namespaceSet bool, namespace string, name string,
+		policy metaV1.DeletionPropagation) (*DeletePreview, error)
+	Resolve(kind string) (*Resource, error)
+}
+
+// Resource describes API resource a kind is mapped to by ResourceVerber.
+type Resource struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// Kind is the name of the kind as registered in apiserver, i.e. 'Deployment'.
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+// DeletePreview lists objects that are removed when the object is deleted with given propagation policy.
+type DeletePreview struct {
+	PropagationPolicy metaV1.DeletionPropagation `json:"propagationPolicy"`
+	Object            ObjectReference            `json:"object"`
+	// Dependents are objects garbage collected together with the object, found through owner references.
+	// It is empty for 'Orphan' propagation policy, as dependents are kept.
+	Dependents []ObjectReference `json:"dependents"`
+}
+
+// ObjectReference identifies object listed in DeletePreview or returned by ResourceVerber.List.
+type ObjectReference struct {
+	Kind       string    `json:"kind"`
+	APIVersion string    `json:"apiVersion"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid"`
+	// Owners lists UIDs of owners of the object.
+	Owners []types.UID `json:"owners,omitempty"`
+	// Controller is UID of the owner that manages the object. It is empty if the object is not managed by
+	// a controller.
+	Controller types.UID `json:"controller,omitempty"`
 }
 
 // CanIResponse represents a response that contains the result of checking whether or not user is allowed
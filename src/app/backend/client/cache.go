@@ -0,0 +1,109 @@
+package client
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sync"
+	"time"
+)
+
+const (
+	// Period after which cached clients are rebuilt even if they are still used.
+	DefaultClientCacheTTL = 5 * time.Minute
+	// Maximal number of cached clients. Least recently used clients are evicted first.
+	DefaultClientCacheSize = 256
+)
+
+// Clients built for a single set of credentials.
+type clientCacheEntry struct {
+	key     string
+	config  *rest.Config
+	client  kubernetes.Interface
+	verber  clientApi.ResourceVerber
+	expires time.Time
+}
+
+// Bounded LRU cache of clients mapped by hash of credentials used to create them. Entries expire after
+// cache TTL or when the credentials expire, whichever comes first.
+type clientCache struct {
+	ttl     time.Duration
+	maxSize int
+
+	entries map[string]*list.Element
+	// Entries ordered from the most to the least recently used.
+	lru *list.List
+	mux sync.Mutex
+}
+
+// Returns entry stored under given key or nil if there is no such entry or it has expired.
+func (self *clientCache) get(key string) *clientCacheEntry {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	element, exists := self.entries[key]
+	if !exists {
+		return nil
+	}
+
+	entry := element.Value.(*clientCacheEntry)
+	if time.Now().After(entry.expires) {
+		self.remove(element)
+		return nil
+	}
+
+	self.lru.MoveToFront(element)
+	return entry
+}
+
+// Stores entry under given key. Entry expires at given time unless cache TTL is shorter. Zero time means
+// that only cache TTL is used.
+func (self *clientCache) add(key string, entry *clientCacheEntry, expires time.Time) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	entry.key = key
+	entry.expires = time.Now().Add(self.ttl)
+	if !expires.IsZero() && expires.Before(entry.expires) {
+		entry.expires = expires
+	}
+
+	if element, exists := self.entries[key]; exists {
+		self.remove(element)
+	}
+
+	self.entries[key] = self.lru.PushFront(entry)
+	for self.lru.Len() > self.maxSize {
+		self.remove(self.lru.Back())
+	}
+}
+
+func (self *clientCache) remove(element *list.Element) {
+	self.lru.Remove(element)
+	delete(self.entries, element.Value.(*clientCacheEntry).key)
+}
+
+// Returns hash of given credentials that is used as a cache key. Credentials are never stored in the cache
+// in plain text.
+func clientCacheKey(credentials interface{}) (string, error) {
+	marshalled, err := json.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(marshalled)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func newClientCache(ttl time.Duration, maxSize int) *clientCache {
+	return &clientCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
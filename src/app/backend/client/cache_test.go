@@ -0,0 +1,57 @@
+package client
+
+import (
+	"github.com/emicklei/go-restful"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClientManager_Client_Cached(t *testing.T) {
+	manager := NewClientManager("", "https://localhost:8080")
+	newRequest := func(token string) *restful.Request {
+		return &restful.Request{Request: &http.Request{
+			Header: http.Header{"Authorization": {"Bearer " + token}},
+		}}
+	}
+
+	first, _ := manager.Client(newRequest("test-token"))
+	second, _ := manager.Client(newRequest("test-token"))
+	other, _ := manager.Client(newRequest("other-token"))
+
+	if first != second {
+		t.Error("Client(): Expected client to be reused for the same token")
+	}
+
+	if first == other {
+		t.Error("Client(): Expected different clients for different tokens")
+	}
+
+	cfg, _ := manager.Config(newRequest("test-token"))
+	cfg.BearerToken = "modified"
+	if cfg, _ = manager.Config(newRequest("test-token")); cfg.BearerToken != "test-token" {
+		t.Errorf("Config(): Expected cached config not to be modified but got token %s", cfg.BearerToken)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	cache := newClientCache(time.Minute, 2)
+
+	cache.add("first", &clientCacheEntry{}, time.Time{})
+	cache.add("second", &clientCacheEntry{}, time.Time{})
+	cache.get("first")
+	cache.add("third", &clientCacheEntry{}, time.Time{})
+
+	if cache.get("second") != nil {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+
+	if cache.get("first") == nil || cache.get("third") == nil {
+		t.Error("Expected recently used entries to be kept")
+	}
+
+	cache.add("expired", &clientCacheEntry{}, time.Now().Add(-time.Second))
+	if cache.get("expired") != nil {
+		t.Error("Expected entry with expired credentials not to be returned")
+	}
+}
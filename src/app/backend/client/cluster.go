@@ -0,0 +1,79 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadClusters reads kubeconfig file or all kubeconfig files from a directory and returns rest configs
+// of the clusters mapped by context names. Every context is registered as a separate cluster, so context
+// names have to be unique across all files.
+func LoadClusters(path string) (map[string]*rest.Config, error) {
+	files, err := getKubeConfigFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make(map[string]*rest.Config)
+	for _, file := range files {
+		config, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load kubeconfig file %s: %s", file, err.Error())
+		}
+
+		if err := clientcmd.ResolveLocalPaths(config); err != nil {
+			return nil, err
+		}
+
+		for name := range config.Contexts {
+			if _, exists := clusters[name]; exists {
+				return nil, fmt.Errorf("Context %s from kubeconfig file %s is already registered.", name, file)
+			}
+
+			cfg, err := clientcmd.NewNonInteractiveClientConfig(*config, name, &clientcmd.ConfigOverrides{},
+				nil).ClientConfig()
+			if err != nil {
+				return nil, fmt.Errorf("Invalid context %s in kubeconfig file %s: %s", name, file, err.Error())
+			}
+
+			clusters[name] = cfg
+		}
+	}
+
+	return clusters, nil
+}
+
+// Returns the given path if it is a file or paths of all files in the directory excluding hidden ones.
+func getKubeConfigFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/auth/jwe"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"io/ioutil"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testClusterKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: %[1]s
+  cluster:
+    server: https://%[1]s:6443
+users:
+- name: %[1]s
+  user:
+    token: %[1]s-token
+contexts:
+- name: %[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s
+current-context: %[1]s
+`
+
+func TestLoadClusters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clusters")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"prod", "staging"} {
+		data := []byte(fmtKubeConfig(name))
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clusters, err := LoadClusters(dir)
+	if err != nil {
+		t.Fatalf("LoadClusters(): Unexpected error: %s", err)
+	}
+
+	if len(clusters) != 2 || clusters["prod"].Host != "https://prod:6443" ||
+		clusters["staging"].BearerToken != "staging-token" {
+		t.Errorf("LoadClusters(): Expected prod and staging clusters but got %#v", clusters)
+	}
+}
+
+func TestClientManager_ClientCmdConfig_Cluster(t *testing.T) {
+	manager := NewClientManager("", "https://localhost:8080")
+	tokenManager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), 0,
+		jwe.DefaultKeyRingSize), nil)
+	manager.SetTokenManager(tokenManager)
+
+	path := writeKubeConfig(t, "prod")
+	defer os.Remove(path)
+
+	clusters, err := LoadClusters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.SetClusters(clusters)
+
+	token, _ := tokenManager.GenerateWithClusters(api.AuthInfo{Token: "default-token"},
+		map[string]api.AuthInfo{"prod": {Token: "user-prod-token"}})
+	defaultOnlyToken, _ := tokenManager.Generate(api.AuthInfo{Token: "default-token"})
+	cases := []struct {
+		cluster       string
+		jweToken      string
+		bearerToken   string
+		expectedHost  string
+		expectedToken string
+		expectedErr   bool
+	}{
+		{"", token, "", "https://localhost:8080", "default-token", false},
+		{"prod", token, "", "https://prod:6443", "user-prod-token", false},
+		{"prod", "", "", "https://prod:6443", "prod-token", false},
+		{"unknown", token, "", "", "", true},
+		// Authenticated users never get credentials of k8sconsole and bearer token is sent to default cluster only.
+		{"prod", defaultOnlyToken, "", "", "", true},
+		{"prod", "", "user-token", "", "", true},
+		{"", "", "user-token", "https://localhost:8080", "user-token", false},
+	}
+
+	for _, c := range cases {
+		req := &restful.Request{Request: &http.Request{Header: http.Header{}}}
+		req.Request.Header.Set(clientApi.ClusterHeader, c.cluster)
+		if len(c.jweToken) > 0 {
+			req.Request.Header.Set(JWETokenHeader, c.jweToken)
+		}
+		if len(c.bearerToken) > 0 {
+			req.Request.Header.Set("Authorization", "Bearer "+c.bearerToken)
+		}
+
+		cfg, err := manager.Config(req)
+		if c.expectedErr && len(c.cluster) > 0 && c.cluster != "unknown" && !k8sErrors.IsUnauthorized(err) {
+			t.Errorf("Config() cluster %s: Expected unauthorized error but got %v", c.cluster, err)
+		}
+
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("Config() cluster %s: Expected error to be %t but got %v", c.cluster, c.expectedErr, err)
+		}
+
+		if err == nil && (cfg.Host != c.expectedHost || cfg.BearerToken != c.expectedToken) {
+			t.Errorf("Config() cluster %s: Expected host %s and token %s but got %s and %s", c.cluster,
+				c.expectedHost, c.expectedToken, cfg.Host, cfg.BearerToken)
+		}
+	}
+}
+
+func TestClientManager_InsecureClusterClient(t *testing.T) {
+	manager := NewClientManager("", "https://localhost:8080")
+	path := writeKubeConfig(t, "prod")
+	defer os.Remove(path)
+
+	clusters, err := LoadClusters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.SetClusters(clusters)
+
+	req := &restful.Request{Request: &http.Request{Header: http.Header{}}}
+	req.Request.Header.Set(clientApi.ClusterHeader, "prod")
+	first, err := manager.InsecureClusterClient(req)
+	if err != nil {
+		t.Fatalf("InsecureClusterClient(): Unexpected error: %s", err)
+	}
+
+	if second, _ := manager.InsecureClusterClient(req); second != first {
+		t.Error("InsecureClusterClient(): Expected client of the cluster to be reused")
+	}
+}
+
+func fmtKubeConfig(name string) string {
+	return fmt.Sprintf(testClusterKubeConfig, name)
+}
+
+func writeKubeConfig(t *testing.T, name string) string {
+	file, err := ioutil.TempFile("", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(fmtKubeConfig(name)); err != nil {
+		t.Fatal(err)
+	}
+
+	return file.Name()
+}
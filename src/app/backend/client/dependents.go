@@ -0,0 +1,206 @@
+package client
+
+import (
+	"encoding/json"
+	clientapi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+)
+
+// Resource of namespaces, whose deletion removes all objects of the namespace.
+var namespaceResource = schema.GroupResource{Resource: "namespaces"}
+
+// Requests lists of object metadata from apiservers that support it and full lists from the others. Lists of
+// both kinds are decoded to partialObjectList, so object bodies are dropped right after they are received.
+const partialObjectListAccept = "application/json;as=PartialObjectMetadataList;v=v1beta1;g=meta.k8s.io, " +
+	"application/json"
+
+// Metadata of raw object, enough to follow owner references.
+type partialObject struct {
+	v1.TypeMeta `json:",inline"`
+	Metadata    v1.ObjectMeta `json:"metadata"`
+}
+
+// List of raw objects. Items of lists returned by apiserver do not contain type metadata.
+type partialObjectList struct {
+	Items []partialObject `json:"items"`
+}
+
+func (self partialObject) reference() clientapi.ObjectReference {
+	reference := clientapi.ObjectReference{
+		Kind:       self.Kind,
+		APIVersion: self.APIVersion,
+		Namespace:  self.Metadata.Namespace,
+		Name:       self.Metadata.Name,
+		UID:        self.Metadata.UID,
+	}
+
+	for _, owner := range self.Metadata.OwnerReferences {
+		reference.Owners = append(reference.Owners, owner.UID)
+		if owner.Controller != nil && *owner.Controller {
+			reference.Controller = owner.UID
+		}
+	}
+
+	return reference
+}
+
+// DeletePreview returns objects that would be garbage collected together with the resource of the given kind
+// in the given namespace with the given name if it was deleted with given propagation policy. Dependents are
+// found by listing all resources the user can list, so the preview is only as complete as user permissions.
+// Every object of a namespace is removed together with it regardless of the policy. Dependents of other cluster
+// scoped objects can live in any namespace, so objects of the whole cluster are listed.
+func (verber *resourceVerber) DeletePreview(kind string, namespaceSet bool, namespace string, name string,
+	policy v1.DeletionPropagation) (*clientapi.DeletePreview, error) {
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := verber.getRaw(mapping, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	object := partialObject{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	preview := &clientapi.DeletePreview{
+		PropagationPolicy: policy,
+		Object:            object.reference(),
+		Dependents:        make([]clientapi.ObjectReference, 0),
+	}
+
+	if mapping.gvr.GroupResource() == namespaceResource {
+		contents, err := verber.listAll(name, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range contents {
+			preview.Dependents = append(preview.Dependents, item.reference())
+		}
+		return preview, nil
+	}
+
+	if policy == v1.DeletePropagationOrphan {
+		return preview, nil
+	}
+
+	// Namespaced objects can be owned only by objects from the same namespace or cluster scoped ones.
+	candidates, err := verber.listAll(namespace, !mapping.namespaced)
+	if err != nil {
+		return nil, err
+	}
+
+	preview.Dependents = collectDependents(object.Metadata.UID, candidates)
+	return preview, nil
+}
+
+// Lists objects of all namespaced resources from the preferred versions of all groups in given namespace, or all
+// namespaces if it is empty, and of cluster scoped resources if requested. Only metadata of objects is requested
+// from apiservers that support it. Resources the user cannot list are skipped.
+func (verber *resourceVerber) listAll(namespace string, clusterScoped bool) ([]partialObject, error) {
+	groups, err := verber.discovery.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]partialObject, 0)
+	seen := make(map[types.UID]bool)
+	for _, group := range groups.Groups {
+		resources, err := verber.discovery.ServerResourcesForGroupVersion(group.PreferredVersion.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(resources.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range resources.APIResources {
+			if strings.Contains(resource.Name, "/") || !hasVerb(resource, "list") ||
+				(!resource.Namespaced && !clusterScoped) {
+				continue
+			}
+
+			mapping := resourceMapping{gvr: gv.WithResource(resource.Name), namespaced: resource.Namespaced}
+			request := verber.client.Get().
+				AbsPath(mapping.apiPath()).
+				Resource(resource.Name).
+				SetHeader("Accept", partialObjectListAccept)
+			if resource.Namespaced {
+				request = request.Namespace(namespace)
+			}
+
+			raw, err := request.Do().Raw()
+			if err != nil {
+				continue
+			}
+
+			list := partialObjectList{}
+			if err := json.Unmarshal(raw, &list); err != nil {
+				continue
+			}
+
+			for _, item := range list.Items {
+				// The same object can be served by multiple groups, i.e. extensions and apps.
+				if seen[item.Metadata.UID] {
+					continue
+				}
+
+				seen[item.Metadata.UID] = true
+				item.Kind = resource.Kind
+				item.APIVersion = gv.String()
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Returns candidates that are garbage collected after object with given UID is removed. Object is collected
+// when all of its owners are removed.
+func collectDependents(uid types.UID, candidates []partialObject) []clientapi.ObjectReference {
+	removed := map[types.UID]bool{uid: true}
+	dependents := make([]clientapi.ObjectReference, 0)
+
+	for changed := true; changed; {
+		changed = false
+		for _, candidate := range candidates {
+			owners := candidate.Metadata.OwnerReferences
+			if removed[candidate.Metadata.UID] || len(owners) == 0 {
+				continue
+			}
+
+			ownersRemoved := true
+			for _, owner := range owners {
+				ownersRemoved = ownersRemoved && removed[owner.UID]
+			}
+
+			if ownersRemoved {
+				removed[candidate.Metadata.UID] = true
+				dependents = append(dependents, candidate.reference())
+				changed = true
+			}
+		}
+	}
+
+	return dependents
+}
+
+func hasVerb(resource v1.APIResource, verb string) bool {
+	for _, v := range resource.Verbs {
+		if v == verb {
+			return true
+		}
+	}
+
+	return false
+}
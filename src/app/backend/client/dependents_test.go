@@ -0,0 +1,138 @@
+package client
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestResourceVerber_DeletePreview(t *testing.T) {
+	responses := map[string]string{
+		"/apis/apps/v1beta2/namespaces/default/deployments/web": `{"kind":"Deployment","apiVersion":"apps/v1beta2",
+			"metadata":{"name":"web","namespace":"default","uid":"deploy"}}`,
+		"/apis/apps/v1beta2/namespaces/default/deployments": `{"items":[
+			{"metadata":{"name":"web","namespace":"default","uid":"deploy"}}]}`,
+		"/apis/apps/v1beta2/namespaces/default/replicasets": `{"items":[
+			{"metadata":{"name":"web-1","namespace":"default","uid":"rs","ownerReferences":[{"uid":"deploy"}]}}]}`,
+		"/api/v1/namespaces/default/pods": `{"items":[
+			{"metadata":{"name":"web-1-a","namespace":"default","uid":"pod-a","ownerReferences":[{"uid":"rs"}]}},
+			{"metadata":{"name":"shared","namespace":"default","uid":"pod-b",
+				"ownerReferences":[{"uid":"rs"},{"uid":"other"}]}},
+			{"metadata":{"name":"single","namespace":"default","uid":"pod-c"}}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+
+	cases := []struct {
+		policy   v1.DeletionPropagation
+		expected []types.UID
+	}{
+		{v1.DeletePropagationForeground, []types.UID{"rs", "pod-a"}},
+		{v1.DeletePropagationBackground, []types.UID{"rs", "pod-a"}},
+		{v1.DeletePropagationOrphan, []types.UID{}},
+	}
+
+	for _, c := range cases {
+		preview, err := testVerber.DeletePreview("deployment", true, "default", "web", c.policy)
+		if err != nil {
+			t.Fatalf("DeletePreview(%s): Unexpected error: %s", c.policy, err)
+		}
+
+		if preview.Object.UID != "deploy" || preview.Object.Kind != "Deployment" {
+			t.Errorf("DeletePreview(%s): Unexpected object %+v", c.policy, preview.Object)
+		}
+
+		actual := make([]types.UID, 0)
+		for _, dependent := range preview.Dependents {
+			actual = append(actual, dependent.UID)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("DeletePreview(%s): Expected dependents %v but got %v", c.policy, c.expected, actual)
+		}
+	}
+}
+
+func TestResourceVerber_DeletePreview_ClusterScoped(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/namespaces/default": `{"kind":"Namespace","apiVersion":"v1",
+			"metadata":{"name":"default","uid":"ns"}}`,
+		"/api/v1/nodes/node-1": `{"kind":"Node","apiVersion":"v1","metadata":{"name":"node-1","uid":"node"}}`,
+		"/api/v1/namespaces/default/pods": `{"items":[
+			{"metadata":{"name":"web-1-a","namespace":"default","uid":"pod-a","ownerReferences":[{"uid":"rs"}]}}]}`,
+		"/apis/apps/v1beta2/namespaces/default/replicasets": `{"items":[
+			{"metadata":{"name":"web-1","namespace":"default","uid":"rs"}}]}`,
+		"/api/v1/pods": `{"items":[
+			{"metadata":{"name":"mirror","namespace":"kube-system","uid":"pod-m","ownerReferences":[{"uid":"node"}]}},
+			{"metadata":{"name":"web-1-a","namespace":"default","uid":"pod-a","ownerReferences":[{"uid":"rs"}]}}]}`,
+	}
+
+	var accepts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		accepts = append(accepts, r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+
+	cases := []struct {
+		kind     string
+		name     string
+		policy   v1.DeletionPropagation
+		expected []types.UID
+	}{
+		// Objects of namespace are removed regardless of the policy
+		{"namespace", "default", v1.DeletePropagationOrphan, []types.UID{"pod-a", "rs"}},
+		{"node", "node-1", v1.DeletePropagationBackground, []types.UID{"pod-m"}},
+	}
+
+	for _, c := range cases {
+		accepts = nil
+		preview, err := testVerber.DeletePreview(c.kind, false, "", c.name, c.policy)
+		if err != nil {
+			t.Fatalf("DeletePreview(%s): Unexpected error: %s", c.kind, err)
+		}
+
+		actual := make([]types.UID, 0)
+		for _, dependent := range preview.Dependents {
+			actual = append(actual, dependent.UID)
+		}
+		// Groups are listed in random order by fake discovery.
+		sort.Slice(actual, func(i, j int) bool { return actual[i] < actual[j] })
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("DeletePreview(%s): Expected dependents %v but got %v", c.kind, c.expected, actual)
+		}
+
+		for _, accept := range accepts[1:] {
+			if accept != partialObjectListAccept {
+				t.Errorf("DeletePreview(%s): Expected metadata of objects to be requested but got %s", c.kind,
+					accept)
+			}
+		}
+	}
+}
@@ -3,16 +3,23 @@ package client
 import (
 	"crypto/rand"
 	"errors"
+	"fmt"
 	"github.com/emicklei/go-restful"
 	"github.com/golang/glog"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
 	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
 	"k8s.io/api/authorization/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -30,8 +37,21 @@ const (
 
 	// Header name that contains token used for authorization.
 	JWETokenHeader = "jweToken"
+
+	// Name of the cluster configured by kubeConfigPath and apiserverHost.
+	DefaultCluster = ""
 )
 
+// Credentials extracted from request.
+type requestCredentials struct {
+	// Name of the cluster request is sent to.
+	cluster string
+	// Identity passed by authenticating proxy. Nil if request has not been sent by the proxy.
+	identity *proxyIdentity
+	// Auth info used to access the cluster. Nil if request does not contain credentials for the cluster.
+	authInfo *api.AuthInfo
+}
+
 // VERSION of this binary
 var Version = "UNKNOWN"
 
@@ -47,24 +67,29 @@ type clientManager struct {
 	inClusterConfig *rest.Config
 	// Responsible for decrypting tokens coming in request header. Used for authentication
 	tokenManager authApi.TokenManager
+	// Rest configs of additional clusters mapped by cluster names. Default cluster is configured by
+	// kubeConfigPath and apiserverHost.
+	clusters map[string]*rest.Config
+	// Clients, configs and verbers cached per credentials
+	clientCache *clientCache
+	// Authenticating proxy whose identity headers are used to impersonate users. Nil if not configured.
+	authProxyConfig *clientApi.AuthProxyConfig
 	// K8s client created without providing auth info. It uses permissions granted to service account used by
 	// k8sconsole or kubeconfig file if it was passed during k8sconsole init
 	insecureClient kubernetes.Interface
+	// Insecure clients of additional clusters mapped by cluster names. They are created on first use.
+	insecureClusterClients map[string]kubernetes.Interface
+	insecureClusterMux     sync.Mutex
 }
 
-// Client returns client to connect to k8s apiserver
+// Client returns client to connect to k8s apiserver. Clients are cached per credentials.
 func (self *clientManager) Client(req *restful.Request) (kubernetes.Interface, error) {
-	cfg, err := self.Config(req)
-	if err != nil {
-		return nil, err
-	}
-
-	client, err := kubernetes.NewForConfig(cfg)
+	entry, err := self.cachedClients(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return client, nil
+	return entry.client, nil
 }
 
 // InsecureClient returns kubernetes client that was created without providing auth info. It uses permissions granted
@@ -73,10 +98,45 @@ func (self *clientManager) InsecureClient() kubernetes.Interface {
 	return self.insecureClient
 }
 
+// InsecureClusterClient returns client of the cluster selected by request that uses credentials from the cluster
+// config instead of credentials of the user. It is the insecure client for the default cluster.
+func (self *clientManager) InsecureClusterClient(req *restful.Request) (kubernetes.Interface, error) {
+	cluster, err := self.extractCluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster == DefaultCluster {
+		return self.insecureClient, nil
+	}
+
+	self.insecureClusterMux.Lock()
+	defer self.insecureClusterMux.Unlock()
+
+	if client, exists := self.insecureClusterClients[cluster]; exists {
+		return client, nil
+	}
+
+	cfg, err := self.clusterConfig(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	self.initConfig(cfg)
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	self.insecureClusterClients[cluster] = client
+	return client, nil
+}
+
 // CanI returns whether user is allowed to access data provided with SelfSubjectAccessReview
 func (self *clientManager) CanI(req *restful.Request, sar *v1.SelfSubjectAccessReview) bool {
 	// If user is not authenticated, do not allow to access
-	if info, _ := self.extractAuthInfo(req); info == nil {
+	if credentials, _ := self.extractCredentials(req); credentials == nil ||
+		(credentials.authInfo == nil && credentials.identity == nil) {
 		return false
 	}
 
@@ -97,7 +157,49 @@ func (self *clientManager) CanI(req *restful.Request, sar *v1.SelfSubjectAccessR
 
 // Config create rest config
 func (self *clientManager) Config(req *restful.Request) (*rest.Config, error) {
-	cmdConfig, err := self.ClientCmdConfig(req)
+	entry, err := self.cachedClients(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Config is shared by cached clients, so the caller gets a copy it can modify
+	return rest.CopyConfig(entry.config), nil
+}
+
+// ClientCmdConfig creates clientcmd config used to create k8s apiserver client
+func (self *clientManager) ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error) {
+	credentials, err := self.extractCredentials(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.buildClientCmdConfig(credentials)
+}
+
+// Returns clients created for credentials extracted from request. Clients are created only if they are
+// not cached yet or cached ones have expired.
+func (self *clientManager) cachedClients(req *restful.Request) (*clientCacheEntry, error) {
+	credentials, err := self.extractCredentials(req)
+	if err != nil {
+		return nil, err
+	}
+
+	keyData := map[string]interface{}{"cluster": credentials.cluster, "authInfo": credentials.authInfo}
+	if credentials.identity != nil {
+		keyData["user"] = credentials.identity.user
+		keyData["groups"] = credentials.identity.groups
+	}
+
+	key, err := clientCacheKey(keyData)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry := self.clientCache.get(key); entry != nil {
+		return entry, nil
+	}
+
+	cmdConfig, err := self.buildClientCmdConfig(credentials)
 	if err != nil {
 		return nil, err
 	}
@@ -108,22 +210,47 @@ func (self *clientManager) Config(req *restful.Request) (*rest.Config, error) {
 	}
 
 	self.initConfig(cfg)
-	return cfg, nil
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &clientCacheEntry{config: cfg, client: client, verber: newResourceVerber(client)}
+	self.clientCache.add(key, entry, credentialsExpiry(credentials.authInfo))
+	return entry, nil
 }
 
-// ClientCmdConfig creates clientcmd config used to create k8s apiserver client
-func (self *clientManager) ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error) {
-	authInfo, err := self.extractAuthInfo(req)
+// Returns cluster selected by request together with identity passed by authenticating proxy or auth info
+// extracted from request. Identity and auth info are nil if request does not contain credentials.
+func (self *clientManager) extractCredentials(req *restful.Request) (*requestCredentials, error) {
+	cluster, err := self.extractCluster(req)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg, err := self.buildConfigFromFlags(self.kubeConfigPath, self.apiserverHost)
+	identity, err := extractProxyIdentity(self.authProxyConfig, req)
+	if err != nil || identity != nil {
+		return &requestCredentials{cluster: cluster, identity: identity}, err
+	}
+
+	authInfo, err := self.extractAuthInfo(req, cluster)
+	return &requestCredentials{cluster: cluster, authInfo: authInfo}, err
+}
+
+// Creates clientcmd config based on credentials extracted from request and config of selected cluster.
+func (self *clientManager) buildClientCmdConfig(credentials *requestCredentials) (clientcmd.ClientConfig, error) {
+	cfg, err := self.clusterConfig(credentials.cluster)
 	if err != nil {
 		return nil, err
 	}
 
+	// User authenticated by trusted proxy is impersonated with auth data provided in cfg
+	if credentials.identity != nil {
+		return self.buildCmdConfig(impersonate(self.buildAuthInfoFromConfig(cfg), credentials.identity), cfg), nil
+	}
+
 	// Use auth data provided in cfg if extracted auth is nil
+	authInfo := credentials.authInfo
 	if authInfo == nil {
 		defaultAuthInfo := self.buildAuthInfoFromConfig(cfg)
 		authInfo = &defaultAuthInfo
@@ -139,7 +266,12 @@ func (self *clientManager) CSRFKey() string {
 
 // HasAccess checks the user with auth info is valid to access the k8s api server
 func (self *clientManager) HasAccess(authInfo api.AuthInfo) error {
-	cfg, err := self.buildConfigFromFlags(self.kubeConfigPath, self.apiserverHost)
+	return self.HasClusterAccess(DefaultCluster, authInfo)
+}
+
+// HasClusterAccess checks the user with auth info is valid to access apiserver of the given cluster
+func (self *clientManager) HasClusterAccess(cluster string, authInfo api.AuthInfo) error {
+	cfg, err := self.clusterConfig(cluster)
 	if err != nil {
 		return err
 	}
@@ -159,16 +291,14 @@ func (self *clientManager) HasAccess(authInfo api.AuthInfo) error {
 	return err
 }
 
-// VerberClient returns new verber client
+// VerberClient returns verber client. Verbers are cached together with clients.
 func (self *clientManager) VerberClient(req *restful.Request) (clientApi.ResourceVerber, error) {
-	client, err := self.Client(req)
+	entry, err := self.cachedClients(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewResourceVerber(client.CoreV1().RESTClient(),
-		client.ExtensionsV1beta1().RESTClient(), client.AppsV1beta2().RESTClient(),
-		client.BatchV1().RESTClient(), client.BatchV1beta1().RESTClient(), client.StorageV1().RESTClient()), nil
+	return entry.verber, nil
 }
 
 // SetTokenManager sets the token manager that will be used for token decryption.
@@ -176,6 +306,32 @@ func (self *clientManager) SetTokenManager(manager authApi.TokenManager) {
 	self.tokenManager = manager
 }
 
+// Clusters returns names of registered clusters excluding the default one.
+func (self *clientManager) Clusters() []string {
+	clusters := make([]string, 0, len(self.clusters))
+	for cluster := range self.clusters {
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Strings(clusters)
+	return clusters
+}
+
+// SetClusters registers additional clusters that can be selected by requests. Rest configs of clusters
+// are mapped by cluster names.
+func (self *clientManager) SetClusters(clusters map[string]*rest.Config) {
+	self.insecureClusterMux.Lock()
+	defer self.insecureClusterMux.Unlock()
+
+	self.clusters = clusters
+	self.insecureClusterClients = make(map[string]kubernetes.Interface)
+}
+
+// SetAuthProxyConfig sets authenticating proxy whose identity headers will be used to impersonate users.
+func (self *clientManager) SetAuthProxyConfig(config *clientApi.AuthProxyConfig) {
+	self.authProxyConfig = config
+}
+
 func (self *clientManager) initConfig(cfg *rest.Config) {
 	cfg.QPS = DefaultQPS
 	cfg.Burst = DefaultBurst
@@ -223,6 +379,14 @@ func (self *clientManager) buildCmdConfig(authInfo *api.AuthInfo, cfg *rest.Conf
 		InsecureSkipTLSVerify:    cfg.TLSClientConfig.Insecure,
 	}
 
+	// Auth provider config embedded in auth info is used only by k8sconsole to refresh credentials,
+	// i.e. OIDC refresh token. Apiserver is accessed with the bearer token.
+	if len(authInfo.Token) > 0 && authInfo.AuthProvider != nil {
+		tokenAuthInfo := *authInfo
+		tokenAuthInfo.AuthProvider = nil
+		authInfo = &tokenAuthInfo
+	}
+
 	cmdCfg.AuthInfos[DefaultCmdConfigName] = authInfo
 
 	cmdCfg.Contexts[DefaultCmdConfigName] = &api.Context{
@@ -237,8 +401,8 @@ func (self *clientManager) buildCmdConfig(authInfo *api.AuthInfo, cfg *rest.Conf
 		&clientcmd.ConfigOverrides{})
 }
 
-// Extracts authorization information from request head
-func (self *clientManager) extractAuthInfo(req *restful.Request) (*api.AuthInfo, error) {
+// Extracts authorization information used to access given cluster from request head
+func (self *clientManager) extractAuthInfo(req *restful.Request, cluster string) (*api.AuthInfo, error) {
 	if req == nil {
 		glog.Warning("No request provided. Skipping authorization")
 		return nil, nil
@@ -247,17 +411,93 @@ func (self *clientManager) extractAuthInfo(req *restful.Request) (*api.AuthInfo,
 	authHeader := req.HeaderParameter("Authorization")
 	jweToken := req.HeaderParameter(JWETokenHeader)
 
-	// Authorization header will be more important than our jwe token
+	// Authorization header will be more important than our jwe token. It is issued for the default cluster, so
+	// it is never sent to other clusters.
 	token := self.extractTokenFromHeader(authHeader)
-	if len(token) > 0 {
+	if len(token) > 0 && cluster == DefaultCluster {
 		return &api.AuthInfo{Token: token}, nil
 	}
 
-	if self.tokenManager != nil && len(jweToken) > 0 {
-		return self.tokenManager.Decrypt(jweToken)
+	if self.tokenManager == nil || len(jweToken) == 0 {
+		if len(token) > 0 {
+			return nil, noClusterCredentialsError(cluster)
+		}
+		return nil, nil
+	}
+
+	authInfo, clusters, err := self.tokenManager.DecryptWithClusters(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster != DefaultCluster {
+		clusterAuthInfo, exists := clusters[cluster]
+		if !exists {
+			return nil, noClusterCredentialsError(cluster)
+		}
+		authInfo = &clusterAuthInfo
+	}
+
+	// Token can hold auth infos of other clusters only. Auth data provided in cluster config is not used for
+	// authenticated users, as it would give them identity of k8sconsole.
+	if reflect.DeepEqual(*authInfo, api.AuthInfo{}) {
+		return nil, noClusterCredentialsError(cluster)
+	}
+
+	return authInfo, nil
+}
+
+// Returns unauthorized error for authenticated request that does not hold credentials of given cluster.
+func noClusterCredentialsError(cluster string) error {
+	return k8sErrors.NewUnauthorized(fmt.Sprintf("No credentials for cluster %q, log in to the cluster first",
+		cluster))
+}
+
+// Returns name of the cluster selected by request. Error is returned if the cluster is not registered.
+func (self *clientManager) extractCluster(req *restful.Request) (string, error) {
+	if req == nil || req.Request == nil {
+		return DefaultCluster, nil
+	}
+
+	cluster := req.HeaderParameter(clientApi.ClusterHeader)
+	if cluster == DefaultCluster {
+		return cluster, nil
+	}
+
+	if _, exists := self.clusters[cluster]; !exists {
+		return "", k8sErrors.NewNotFound(schema.GroupResource{Resource: "clusters"}, cluster)
+	}
+
+	return cluster, nil
+}
+
+// Returns rest config of given cluster. Config of the default cluster is based on flags.
+func (self *clientManager) clusterConfig(cluster string) (*rest.Config, error) {
+	if cluster == DefaultCluster {
+		return self.buildConfigFromFlags(self.kubeConfigPath, self.apiserverHost)
+	}
+
+	cfg, exists := self.clusters[cluster]
+	if !exists {
+		return nil, k8sErrors.NewNotFound(schema.GroupResource{Resource: "clusters"}, cluster)
+	}
+
+	return rest.CopyConfig(cfg), nil
+}
+
+// Returns expiration time of credentials or zero time if it is unknown. Only ID tokens obtained from
+// OIDC issuer carry their expiration time.
+func credentialsExpiry(authInfo *api.AuthInfo) time.Time {
+	if authInfo == nil || authInfo.AuthProvider == nil {
+		return time.Time{}
+	}
+
+	expiry, err := time.Parse(time.RFC3339, authInfo.AuthProvider.Config[authApi.OIDCExpiryKey])
+	if err != nil {
+		return time.Time{}
 	}
 
-	return nil, nil
+	return expiry
 }
 
 func (self *clientManager) extractTokenFromHeader(authHeader string) string {
@@ -269,6 +509,7 @@ func (self *clientManager) extractTokenFromHeader(authHeader string) string {
 }
 
 func (self *clientManager) init() {
+	self.clientCache = newClientCache(DefaultClientCacheTTL, DefaultClientCacheSize)
 	self.initInClusterConfig()
 	self.initCSRFKey()
 	self.initInsecureClient()
@@ -333,8 +574,9 @@ func (self *clientManager) isRunningInCluster() bool {
 // If both are empty then in-cluster config is used.
 func NewClientManager(kubeConfigPath, apiserverHost string) clientApi.ClientManager {
 	result := &clientManager{
-		kubeConfigPath: kubeConfigPath,
-		apiserverHost:  apiserverHost,
+		kubeConfigPath:         kubeConfigPath,
+		apiserverHost:          apiserverHost,
+		insecureClusterClients: make(map[string]kubernetes.Interface),
 	}
 
 	result.init()
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"crypto/x509"
+	"errors"
+	"github.com/emicklei/go-restful"
+	"github.com/golang/glog"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"net"
+	"strings"
+)
+
+// Identity of the user passed by authenticating proxy.
+type proxyIdentity struct {
+	user   string
+	groups []string
+}
+
+// Extracts user identity passed by authenticating proxy. Returns nil if the proxy is not configured or request
+// does not contain user header. Error is returned if request with user header comes from untrusted source.
+func extractProxyIdentity(config *clientApi.AuthProxyConfig, req *restful.Request) (*proxyIdentity, error) {
+	if config == nil || len(config.UserHeader) == 0 || req == nil || req.Request == nil {
+		return nil, nil
+	}
+
+	user := req.Request.Header.Get(config.UserHeader)
+	if len(user) == 0 {
+		return nil, nil
+	}
+
+	if !isTrustedProxy(config, req) {
+		glog.Warningf("Rejecting %s header sent from untrusted source %s", config.UserHeader,
+			req.Request.RemoteAddr)
+		return nil, errors.New("Identity headers are accepted only from trusted authenticating proxy.")
+	}
+
+	identity := &proxyIdentity{user: user, groups: make([]string, 0)}
+	if len(config.GroupHeader) == 0 {
+		return identity, nil
+	}
+
+	for _, value := range req.Request.Header[config.GroupHeader] {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); len(group) > 0 {
+				identity.groups = append(identity.groups, group)
+			}
+		}
+	}
+
+	return identity, nil
+}
+
+// Returns true if request comes from trusted network or presents client certificate signed by trusted CA.
+func isTrustedProxy(config *clientApi.AuthProxyConfig, req *restful.Request) bool {
+	host, _, err := net.SplitHostPort(req.Request.RemoteAddr)
+	if err != nil {
+		host = req.Request.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, cidr := range config.TrustedCIDRs {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	if config.ClientCA == nil || req.Request.TLS == nil || len(req.Request.TLS.PeerCertificates) == 0 {
+		return false
+	}
+
+	certs := req.Request.TLS.PeerCertificates
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         config.ClientCA,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err == nil
+}
+
+// Returns auth info that impersonates given identity using base auth info credentials.
+func impersonate(authInfo api.AuthInfo, identity *proxyIdentity) *api.AuthInfo {
+	authInfo.Impersonate = identity.user
+	authInfo.ImpersonateGroups = identity.groups
+	return &authInfo
+}
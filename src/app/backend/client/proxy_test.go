@@ -0,0 +1,84 @@
+package client
+
+import (
+	"github.com/emicklei/go-restful"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClientManager_ClientCmdConfig_AuthProxy(t *testing.T) {
+	_, trusted, _ := net.ParseCIDR("10.0.0.0/8")
+	manager := NewClientManager("", "https://localhost:8080")
+	manager.SetAuthProxyConfig(&clientApi.AuthProxyConfig{
+		UserHeader:   "X-Remote-User",
+		GroupHeader:  "X-Remote-Group",
+		TrustedCIDRs: []*net.IPNet{trusted},
+	})
+
+	cases := []struct {
+		info           string
+		remoteAddr     string
+		header         http.Header
+		expectedUser   string
+		expectedGroups []string
+		expectedErr    bool
+	}{
+		{
+			"trusted proxy",
+			"10.1.2.3:4567",
+			http.Header{
+				"X-Remote-User":  {"alice"},
+				"X-Remote-Group": {"dev, ops", "admins"},
+			},
+			"alice",
+			[]string{"dev", "ops", "admins"},
+			false,
+		},
+		{
+			"untrusted source",
+			"192.168.1.1:4567",
+			http.Header{"X-Remote-User": {"alice"}},
+			"",
+			nil,
+			true,
+		},
+		{
+			"no identity headers",
+			"192.168.1.1:4567",
+			http.Header{},
+			"",
+			nil,
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		req := &restful.Request{Request: &http.Request{RemoteAddr: c.remoteAddr, Header: c.header}}
+		cmdCfg, err := manager.ClientCmdConfig(req)
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("ClientCmdConfig() %s: Expected error to be %t but got %v", c.info, c.expectedErr, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		cfg, err := cmdCfg.ClientConfig()
+		if err != nil {
+			t.Fatalf("ClientCmdConfig() %s: Expected config to be created but error was thrown: %s", c.info, err)
+		}
+
+		if cfg.Impersonate.UserName != c.expectedUser {
+			t.Errorf("ClientCmdConfig() %s: Expected impersonated user %s but got %s", c.info, c.expectedUser,
+				cfg.Impersonate.UserName)
+		}
+
+		if len(c.expectedGroups) > 0 && !reflect.DeepEqual(cfg.Impersonate.Groups, c.expectedGroups) {
+			t.Errorf("ClientCmdConfig() %s: Expected impersonated groups %v but got %v", c.info, c.expectedGroups,
+				cfg.Impersonate.Groups)
+		}
+	}
+}
@@ -1,145 +1,448 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/wzt3309/k8sconsole/src/app/backend/api"
 	clientapi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"github.com/wzt3309/k8sconsole/src/app/backend/dryrun"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"path"
+	"sort"
+	"sync"
+	"time"
 )
 
-// resourceVerber is a struct responsible for doing CRUD operations on resources
+// Legacy groups that still serve copies of resources moved to other groups, i.e. deployments in extensions.
+// Resources are mapped to them only if no other group serves them.
+var legacyGroups = map[string]bool{"extensions": true}
+
+// Minimal interval between discoveries triggered by unknown kinds. Verbers are cached per user, so without it
+// every request with unknown kind would make apiserver run the discovery.
+const minRediscoveryInterval = 30 * time.Second
+
+// resourceVerber is a struct responsible for doing CRUD operations on resources. Kinds are mapped
+// to resources using apiserver discovery, so every group, version and kind served by apiserver is supported,
+// including custom resources.
 type resourceVerber struct {
-	client           RESTClient
-	extensionsClient RESTClient
-	appsClient       RESTClient
-	batchClient      RESTClient
-	betaBatchClient  RESTClient
-	storageClient    RESTClient
-}
-
-func (verber *resourceVerber) getRESTClientByType(clientType api.ClientType) RESTClient {
-	switch clientType {
-	case api.ClientTypeExtensionClient:
-		return verber.extensionsClient
-	case api.ClientTypeAppsClient:
-		return verber.appsClient
-	case api.ClientTypeBatchClient:
-		return verber.batchClient
-	case api.ClientTypeBetaBatchClient:
-		return verber.betaBatchClient
-	case api.ClientTypeStorageClient:
-		return verber.storageClient
-	default:
-		return verber.client
-	}
+	client    RESTClient
+	discovery discovery.DiscoveryInterface
+
+	// Lazily initialized mapper built from discovery information. It is reset when kind is not found,
+	// so resources registered later, i.e. new custom resources, are discovered.
+	mapper meta.RESTMapper
+	// Kinds of resources read from discovery information together with the mapper. Kinds returned by the
+	// mapper can be lowercased.
+	kinds map[schema.GroupVersionResource]string
+	// Time the mapper has been built at.
+	discovered time.Time
+	mux        sync.Mutex
 }
 
 // RESTClient is an interface for REST operations used in the file
 type RESTClient interface {
+	Post() *rest.Request
 	Put() *rest.Request
 	Get() *rest.Request
 	Delete() *rest.Request
+	Patch(pt types.PatchType) *rest.Request
+}
+
+// Resource the kind is mapped to.
+type resourceMapping struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
+// Returns absolute path of the API group version of the resource, i.e. '/apis/apps/v1'.
+func (self resourceMapping) apiPath() string {
+	if len(self.gvr.Group) == 0 {
+		return path.Join("/api", self.gvr.Version)
+	}
+
+	return path.Join("/apis", self.gvr.Group, self.gvr.Version)
+}
+
+// Create creates new resource of the given kind in the given namespace and returns created resource.
+func (verber *resourceVerber) Create(kind string, namespaceSet bool, namespace string,
+	object *runtime.Unknown) (runtime.Object, error) {
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return nil, err
+	}
+
+	req := verber.client.Post().
+		AbsPath(mapping.apiPath()).
+		Resource(mapping.gvr.Resource).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "application/json").
+		Body([]byte(object.Raw))
+
+	if mapping.namespaced {
+		req.Namespace(namespace)
+	}
+
+	raw, err := req.Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.Unknown{Raw: raw, ContentType: runtime.ContentTypeJSON}, nil
 }
 
 // Put puts new resource version of the given kind in the given namespace with the given name.
 func (verber *resourceVerber) Put(kind string, namespaceSet bool, namespace string, name string,
 	object *runtime.Unknown) error {
-	resourceSpec, ok := api.KindToAPIMapping[kind]
-	if !ok {
-		return fmt.Errorf("Unknown resource kind: %s", kind)
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return err
 	}
 
-	if namespaceSet != resourceSpec.Namespaced {
-		if namespaceSet {
-			return fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
-		} else {
-			return fmt.Errorf("Set no-namespaced for namespaced resource kind: %s", kind)
-		}
+	return verber.putRequest(mapping, namespace, name, object).Do().Error()
+}
+
+// PutDryRun sends Put request with dry-run and returns what it would change in the live resource.
+func (verber *resourceVerber) PutDryRun(kind string, namespaceSet bool, namespace string, name string,
+	object *runtime.Unknown) (*dryrun.Result, error) {
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return nil, err
 	}
 
-	client := verber.getRESTClientByType(resourceSpec.ClientType)
+	return verber.dryRun(mapping, namespace, name, verber.putRequest(mapping, namespace, name, object))
+}
 
-	req := client.Put().
-		Resource(resourceSpec.Resource).
+func (verber *resourceVerber) putRequest(mapping *resourceMapping, namespace string, name string,
+	object *runtime.Unknown) *rest.Request {
+	req := verber.client.Put().
+		AbsPath(mapping.apiPath()).
+		Resource(mapping.gvr.Resource).
 		Name(name).
 		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "application/json").
 		Body([]byte(object.Raw))
 
-	if resourceSpec.Namespaced {
+	if mapping.namespaced {
 		req.Namespace(namespace)
 	}
 
-	return req.Do().Error()
+	return req
 }
 
 // Get gets the resource of the given kind in the given namespace with the given name.
 func (verber *resourceVerber) Get(kind string, namespaceSet bool, namespace string, name string) (
 	runtime.Object, error) {
-	resourceSpec, ok := api.KindToAPIMapping[kind]
-	if !ok {
-		return nil, fmt.Errorf("Unknown resource kind: %s", kind)
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return nil, err
 	}
 
-	if namespaceSet != resourceSpec.Namespaced {
-		if namespaceSet {
-			return nil, fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
-		} else {
-			return nil, fmt.Errorf("Set no-namespaced for namespaced resource kind: %s", kind)
-		}
+	raw, err := verber.getRaw(mapping, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.Unknown{Raw: raw, ContentType: runtime.ContentTypeJSON}, nil
+}
+
+// List returns references to objects of the given kind matching the given options. Objects of namespaced kinds
+// are listed from the given namespace or from all namespaces if it is empty. Namespace is ignored for cluster
+// scoped kinds.
+func (verber *resourceVerber) List(kind string, namespace string, options v1.ListOptions) (
+	[]clientapi.ObjectReference, error) {
+	mapping, err := verber.lookup(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	req := verber.client.Get().
+		AbsPath(mapping.apiPath()).
+		Resource(mapping.gvr.Resource).
+		SetHeader("Accept", "application/json")
+
+	if mapping.namespaced {
+		req.Namespace(namespace)
+	}
+	if len(options.LabelSelector) > 0 {
+		req.Param("labelSelector", options.LabelSelector)
+	}
+	if len(options.FieldSelector) > 0 {
+		req.Param("fieldSelector", options.FieldSelector)
+	}
+
+	raw, err := req.Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	list := partialObjectList{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
 	}
 
-	client := verber.getRESTClientByType(resourceSpec.ClientType)
-	result := &runtime.Unknown{}
-	req := client.Get().
-		Resource(resourceSpec.Resource).
+	result := make([]clientapi.ObjectReference, 0, len(list.Items))
+	for _, item := range list.Items {
+		item.Kind = mapping.kind
+		item.APIVersion = mapping.gvr.GroupVersion().String()
+		result = append(result, item.reference())
+	}
+
+	return result, nil
+}
+
+func (verber *resourceVerber) getRaw(mapping *resourceMapping, namespace string, name string) ([]byte, error) {
+	req := verber.client.Get().
+		AbsPath(mapping.apiPath()).
+		Resource(mapping.gvr.Resource).
 		Name(name).
 		SetHeader("Accept", "application/json")
 
-	if resourceSpec.Namespaced {
+	if mapping.namespaced {
 		req.Namespace(namespace)
 	}
 
-	err := req.Do().Into(result)
-	return result, err
+	return req.Do().Raw()
+}
+
+// Patch applies patch of given type to the resource of the given kind in the given namespace with the given name
+// and returns patched resource.
+func (verber *resourceVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, patch []byte) (runtime.Object, error) {
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := verber.patchRequest(mapping, namespace, name, patchType, patch).Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.Unknown{Raw: raw, ContentType: runtime.ContentTypeJSON}, nil
 }
 
-// Delete deletes the resource of the given kind in the given namespace with the given name.
-func (verber *resourceVerber) Delete(kind string, namespaceSet bool, namespace string, name string) error {
-	resourceSpec, ok := api.KindToAPIMapping[kind]
-	if !ok {
-		return fmt.Errorf("Unknown resource kind: %s", kind)
+// PatchDryRun sends Patch request with dry-run and returns what it would change in the live resource.
+func (verber *resourceVerber) PatchDryRun(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, patch []byte) (*dryrun.Result, error) {
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return nil, err
 	}
 
-	if namespaceSet != resourceSpec.Namespaced {
+	return verber.dryRun(mapping, namespace, name, verber.patchRequest(mapping, namespace, name, patchType, patch))
+}
+
+func (verber *resourceVerber) patchRequest(mapping *resourceMapping, namespace string, name string,
+	patchType types.PatchType, patch []byte) *rest.Request {
+	req := verber.client.Patch(patchType).
+		AbsPath(mapping.apiPath()).
+		Resource(mapping.gvr.Resource).
+		Name(name).
+		SetHeader("Accept", "application/json").
+		Body(patch)
+
+	if mapping.namespaced {
+		req.Namespace(namespace)
+	}
+
+	return req
+}
+
+// Sends given request with dry-run and compares the result with the live resource. Fails if apiserver does not
+// support dry-run, so the request is never persisted.
+func (verber *resourceVerber) dryRun(mapping *resourceMapping, namespace string, name string,
+	req *rest.Request) (*dryrun.Result, error) {
+	if err := dryrun.CheckSupported(verber.discovery); err != nil {
+		return nil, err
+	}
+
+	live, err := verber.getRaw(mapping, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := req.Param(dryrun.DryRunParam, dryrun.DryRunAll).Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	return dryrun.NewResult(live, result)
+}
+
+// Delete deletes the resource of the given kind in the given namespace with the given name. If options are nil,
+// dependents are deleted in the foreground.
+func (verber *resourceVerber) Delete(kind string, namespaceSet bool, namespace string, name string,
+	options *v1.DeleteOptions) error {
+	mapping, err := verber.mapping(kind, namespaceSet)
+	if err != nil {
+		return err
+	}
+
+	if options == nil {
+		// Do cascade delete by default, as this is what users typically expect.
+		defaultPropagationPolicy := v1.DeletePropagationForeground
+		options = &v1.DeleteOptions{PropagationPolicy: &defaultPropagationPolicy}
+	}
+
+	body, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	req := verber.client.Delete().
+		AbsPath(mapping.apiPath()).
+		Resource(mapping.gvr.Resource).
+		Name(name).
+		SetHeader("Content-Type", "application/json").
+		Body(body)
+
+	if mapping.namespaced {
+		req.Namespace(namespace)
+	}
+
+	return req.Do().Error()
+}
+
+// Resolve returns resource the given kind, i.e. 'deployment', 'deployments' or 'Deployment', is mapped to.
+func (verber *resourceVerber) Resolve(kind string) (*clientapi.Resource, error) {
+	mapping, err := verber.lookup(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientapi.Resource{
+		Group:      mapping.gvr.Group,
+		Version:    mapping.gvr.Version,
+		Resource:   mapping.gvr.Resource,
+		Kind:       mapping.kind,
+		Namespaced: mapping.namespaced,
+	}, nil
+}
+
+// Maps kind to the resource in the preferred version of its group. Kind can be given as lower case kind,
+// singular or plural resource name, optionally followed by the group, i.e. 'deployment' or 'crontabs.stable.example.com'.
+func (verber *resourceVerber) mapping(kind string, namespaceSet bool) (*resourceMapping, error) {
+	mapping, err := verber.lookup(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if namespaceSet != mapping.namespaced {
 		if namespaceSet {
-			return fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
+			return nil, fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
 		} else {
-			return fmt.Errorf("Set no namespace for namespaced resource kind: %s", kind)
+			return nil, fmt.Errorf("Set no namespace for namespaced resource kind: %s", kind)
 		}
 	}
 
-	client := verber.getRESTClientByType(resourceSpec.ClientType)
+	return mapping, nil
+}
 
-	// Do cascade delete by default, as this is what users typically expect.
-	defaultPropagationPolicy := v1.DeletePropagationForeground
-	defaultDeleteOptions := &v1.DeleteOptions{
-		PropagationPolicy: &defaultPropagationPolicy,
+// Returns resource the kind is mapped to regardless of its scope.
+func (verber *resourceVerber) lookup(kind string) (*resourceMapping, error) {
+	mapping, err := verber.resolve(kind)
+	if meta.IsNoMatchError(err) && verber.reset() {
+		// Kind could be registered after discovery information was read.
+		mapping, err = verber.resolve(kind)
 	}
 
-	req := client.Delete().Resource(resourceSpec.Resource).Name(name).Body(defaultDeleteOptions)
+	if meta.IsNoMatchError(err) {
+		return nil, k8sErrors.NewNotFound(schema.GroupResource{Resource: "resource kind"}, kind)
+	}
 
-	if resourceSpec.Namespaced {
-		req.Namespace(namespace)
+	return mapping, err
+}
+
+func (verber *resourceVerber) resolve(kind string) (*resourceMapping, error) {
+	mapper, kinds, err := verber.getMapper()
+	if err != nil {
+		return nil, err
 	}
 
-	return req.Do().Error()
+	gvr, err := mapper.ResourceFor(schema.ParseGroupResource(kind).WithVersion(""))
+	if err != nil {
+		return nil, err
+	}
+
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	restMapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resourceMapping{
+		gvr:        gvr,
+		kind:       kinds[gvr],
+		namespaced: restMapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+func (verber *resourceVerber) getMapper() (meta.RESTMapper, map[schema.GroupVersionResource]string, error) {
+	verber.mux.Lock()
+	defer verber.mux.Unlock()
+
+	if verber.mapper != nil {
+		return verber.mapper, verber.kinds, nil
+	}
+
+	groupResources, err := discovery.GetAPIGroupResources(verber.discovery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verber.kinds = make(map[schema.GroupVersionResource]string)
+	for _, group := range groupResources {
+		for version, resources := range group.VersionedResources {
+			for _, resource := range resources {
+				gvr := schema.GroupVersionResource{Group: group.Group.Name, Version: version, Resource: resource.Name}
+				verber.kinds[gvr] = resource.Kind
+			}
+		}
+	}
+
+	sort.SliceStable(groupResources, func(i, j int) bool {
+		return !legacyGroups[groupResources[i].Group.Name] && legacyGroups[groupResources[j].Group.Name]
+	})
+
+	verber.mapper = discovery.NewRESTMapper(groupResources, dynamic.VersionInterfaces)
+	verber.discovered = time.Now()
+	return verber.mapper, verber.kinds, nil
+}
+
+// Resets the mapper, so discovery information is read again. Returns false if the mapper has been built less than
+// minRediscoveryInterval ago and is kept.
+func (verber *resourceVerber) reset() bool {
+	verber.mux.Lock()
+	defer verber.mux.Unlock()
+
+	if time.Since(verber.discovered) < minRediscoveryInterval {
+		return false
+	}
+
+	verber.mapper = nil
+	return true
+}
+
+// NewResourceVerber creates a new resource verber that uses the given client for performing operations
+// and discovery client to map kinds to resources. Client has to accept absolute paths, i.e. discovery REST client.
+func NewResourceVerber(client RESTClient, discovery discovery.DiscoveryInterface) clientapi.ResourceVerber {
+	return &resourceVerber{client: client, discovery: discovery}
 }
 
-// NewResourceVerber creates a new resource verber that uses the given client for performing operations.
-func NewResourceVerber(client, extensionsClient, appsClient,
-batchClient, betaBatchClient, storageClient RESTClient) clientapi.ResourceVerber {
-	return &resourceVerber{client, extensionsClient, appsClient,
-		batchClient, betaBatchClient, storageClient}
+// Creates verber using discovery client of given clientset.
+func newResourceVerber(client kubernetes.Interface) clientapi.ResourceVerber {
+	return NewResourceVerber(client.Discovery().RESTClient(), client.Discovery())
 }
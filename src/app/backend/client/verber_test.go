@@ -1,29 +1,269 @@
 package client
 
 import (
+	"encoding/json"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
+// Creates verber that maps kinds using fake discovery and sends requests to given server.
+func newTestVerber(t *testing.T, server *httptest.Server) *resourceVerber {
+	discovery := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metaV1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metaV1.APIResource{
+			{Name: "pods", SingularName: "pod", Namespaced: true, Kind: "Pod", Verbs: []string{"list"}},
+			{Name: "nodes", SingularName: "node", Namespaced: false, Kind: "Node"},
+			{Name: "namespaces", SingularName: "namespace", Namespaced: false, Kind: "Namespace",
+				Verbs: []string{"list"}},
+		}},
+		{GroupVersion: "extensions/v1beta1", APIResources: []metaV1.APIResource{
+			{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment"},
+		}},
+		{GroupVersion: "apps/v1beta2", APIResources: []metaV1.APIResource{
+			{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment",
+				Verbs: []string{"list"}},
+			{Name: "replicasets", SingularName: "replicaset", Namespaced: true, Kind: "ReplicaSet",
+				Verbs: []string{"list"}},
+		}},
+		{GroupVersion: "stable.example.com/v1", APIResources: []metaV1.APIResource{
+			{Name: "crontabs", SingularName: "crontab", Namespaced: true, Kind: "CronTab"},
+		}},
+	}
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewResourceVerber(client.Discovery().RESTClient(), discovery).(*resourceVerber)
+}
+
 func TestResourceVerber_Put(t *testing.T) {
-	testClient := fake.NewSimpleClientset()
-	testVerber := NewResourceVerber(testClient.CoreV1().RESTClient(),
-		testClient.ExtensionsV1beta1().RESTClient(), testClient.AppsV1beta2().RESTClient(),
-		testClient.BatchV1().RESTClient(), testClient.BatchV1beta1().RESTClient(), testClient.StorageV1().RESTClient())
-
-	//reqObj := &v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "foo-name"}}
-	//reqBodyExpected, err := runtime.Encode(scheme.Codecs.LegacyCodec(v1.SchemeGroupVersion), reqObj)
-	//if err != nil {
-	//	t.Errorf("unexpected error: %v", err)
-	//}
-
-	object := &runtime.Unknown{
-		TypeMeta: runtime.TypeMeta{
-			APIVersion: "v1",
-			Kind: "Pod",
-		},
-		//Raw: reqBodyExpected,
-	}
-	testVerber.Put("pods", true,"default", "foo-name", object)
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+
+	cases := []struct {
+		kind         string
+		namespaceSet bool
+		expected     string
+		expectedErr  bool
+	}{
+		{"pod", true, "PUT /api/v1/namespaces/default/pods/foo-name", false},
+		{"deployment", true, "PUT /apis/apps/v1beta2/namespaces/default/deployments/foo-name", false},
+		{"deployments.extensions", true, "PUT /apis/extensions/v1beta1/namespaces/default/deployments/foo-name", false},
+		{"crontab.stable.example.com", true, "PUT /apis/stable.example.com/v1/namespaces/default/crontabs/foo-name", false},
+		{"node", false, "PUT /api/v1/nodes/foo-name", false},
+		{"node", true, "", true},
+		{"unknown", true, "", true},
+	}
+
+	for _, c := range cases {
+		requests = nil
+		object := &runtime.Unknown{
+			TypeMeta: runtime.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Pod",
+			},
+			Raw: []byte("{}"),
+		}
+
+		err := testVerber.Put(c.kind, c.namespaceSet, "default", "foo-name", object)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("Put(%s): Expected error to be %t but got %v", c.kind, c.expectedErr, err)
+			continue
+		}
+
+		if !c.expectedErr && (len(requests) != 1 || requests[0] != c.expected) {
+			t.Errorf("Put(%s): Expected request %s but got %v", c.kind, c.expected, requests)
+		}
+	}
+}
+
+func TestResourceVerber_Patch(t *testing.T) {
+	var method, path, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, contentType = r.Method, r.URL.Path, r.Header.Get("Content-Type")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"Deployment"}`))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+	result, err := testVerber.Patch("deployment", true, "default", "foo-name", types.MergePatchType,
+		[]byte(`{"spec":{"replicas":3}}`))
+	if err != nil {
+		t.Fatalf("Patch(): Unexpected error: %s", err)
+	}
+
+	if method != http.MethodPatch || path != "/apis/apps/v1beta2/namespaces/default/deployments/foo-name" ||
+		contentType != string(types.MergePatchType) {
+		t.Errorf("Patch(): Unexpected request %s %s with Content-Type %s", method, path, contentType)
+	}
+
+	if raw := string(result.(*runtime.Unknown).Raw); raw != `{"kind":"Deployment"}` {
+		t.Errorf("Patch(): Expected patched object to be returned but got %s", raw)
+	}
+}
+
+func TestResourceVerber_Create(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(path, "/namespaces/existing/") {
+			status := k8sErrors.NewAlreadyExists(schema.GroupResource{Resource: "pods"}, "foo-name").ErrStatus
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(&status)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"kind":"Pod"}`))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+	object := &runtime.Unknown{Raw: []byte(`{"kind":"Pod","metadata":{"name":"foo-name"}}`)}
+
+	if _, err := testVerber.Create("pod", true, "default", object); err != nil {
+		t.Fatalf("Create(): Unexpected error: %s", err)
+	}
+
+	if method != http.MethodPost || path != "/api/v1/namespaces/default/pods" {
+		t.Errorf("Create(): Unexpected request %s %s", method, path)
+	}
+
+	_, err := testVerber.Create("pod", true, "existing", object)
+	if !k8sErrors.IsAlreadyExists(err) {
+		t.Errorf("Create(): Expected already exists error but got %v", err)
+	}
+}
+
+func TestResourceVerber_PutDryRun(t *testing.T) {
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"kind":"Pod","metadata":{"name":"foo-name","resourceVersion":"1"},"spec":{"x":1}}`))
+			return
+		}
+
+		writes = append(writes, r.Method+" "+r.URL.RawQuery)
+		w.Write([]byte(`{"kind":"Pod","metadata":{"name":"foo-name","resourceVersion":"2"},"spec":{"x":2}}`))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+	discovery := testVerber.discovery.(*fakediscovery.FakeDiscovery)
+	object := &runtime.Unknown{Raw: []byte(`{"kind":"Pod","metadata":{"name":"foo-name"},"spec":{"x":2}}`)}
+
+	discovery.FakedServerVersion = &version.Info{Major: "1", Minor: "9"}
+	if _, err := testVerber.PutDryRun("pod", true, "default", "foo-name", object); err == nil || len(writes) > 0 {
+		t.Fatalf("PutDryRun(): Expected error and no request for apiserver without dry-run support, got %v %v",
+			err, writes)
+	}
+
+	discovery.FakedServerVersion = &version.Info{Major: "1", Minor: "13"}
+	result, err := testVerber.PutDryRun("pod", true, "default", "foo-name", object)
+	if err != nil {
+		t.Fatalf("PutDryRun(): Unexpected error: %s", err)
+	}
+
+	if len(writes) != 1 || writes[0] != "PUT dryRun=All" {
+		t.Errorf("PutDryRun(): Expected single dry-run PUT but got %v", writes)
+	}
+
+	if len(result.Changes) != 1 || result.Changes[0].Path != "/spec/x" {
+		t.Errorf("PutDryRun(): Expected only /spec/x to change but got %+v", result.Changes)
+	}
+}
+
+func TestResourceVerber_List(t *testing.T) {
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"metadata":{"name":"web","namespace":"test","uid":"1"}}]}`))
+	}))
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+	result, err := testVerber.List("deployment", "", metaV1.ListOptions{LabelSelector: "app=web"})
+	if err != nil {
+		t.Fatalf("List(): Unexpected error: %s", err)
+	}
+
+	if path != "/apis/apps/v1beta2/deployments" || query != "labelSelector=app%3Dweb" {
+		t.Errorf("List(): Unexpected request %s?%s", path, query)
+	}
+
+	if len(result) != 1 || result[0].Kind != "Deployment" || result[0].APIVersion != "apps/v1beta2" ||
+		result[0].Namespace != "test" || result[0].Name != "web" {
+		t.Errorf("List(): Unexpected result %+v", result)
+	}
+}
+
+func TestResourceVerber_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+	for _, kind := range []string{"deployment", "deployments", "Deployment"} {
+		resource, err := testVerber.Resolve(kind)
+		if err != nil {
+			t.Fatalf("Resolve(%s): Unexpected error: %s", kind, err)
+		}
+
+		if resource.Kind != "Deployment" || resource.Group != "apps" || !resource.Namespaced {
+			t.Errorf("Resolve(%s): Expected namespaced Deployment of apps group but got %+v", kind, resource)
+		}
+	}
+}
+
+func TestResourceVerber_Rediscovery(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	testVerber := newTestVerber(t, server)
+	discovery := testVerber.discovery.(*fakediscovery.FakeDiscovery)
+	if _, err := testVerber.Resolve("pod"); err != nil {
+		t.Fatalf("Resolve(pod): Unexpected error: %s", err)
+	}
+
+	actions := len(discovery.Actions())
+	if _, err := testVerber.Resolve("foo"); !k8sErrors.IsNotFound(err) {
+		t.Errorf("Resolve(foo): Expected not found error but got %v", err)
+	}
+
+	if len(discovery.Actions()) != actions {
+		t.Errorf("Resolve(foo): Expected no discovery within %s but got %v", minRediscoveryInterval,
+			discovery.Actions()[actions:])
+	}
+
+	discovery.Resources = append(discovery.Resources, &metaV1.APIResourceList{GroupVersion: "example.com/v1",
+		APIResources: []metaV1.APIResource{{Name: "foos", SingularName: "foo", Namespaced: true, Kind: "Foo"}}})
+	testVerber.discovered = time.Now().Add(-minRediscoveryInterval)
+	if resource, err := testVerber.Resolve("foo"); err != nil || resource.Kind != "Foo" {
+		t.Errorf("Resolve(foo): Expected kind registered later to be discovered but got %+v, %v", resource, err)
+	}
 }
@@ -1,17 +1,27 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/spf13/pflag"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
 	"github.com/wzt3309/k8sconsole/src/app/backend/args"
 	"github.com/wzt3309/k8sconsole/src/app/backend/auth"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
 	"github.com/wzt3309/k8sconsole/src/app/backend/auth/jwe"
+	"github.com/wzt3309/k8sconsole/src/app/backend/auth/oidc"
+	"github.com/wzt3309/k8sconsole/src/app/backend/cert"
+	certApi "github.com/wzt3309/k8sconsole/src/app/backend/cert/api"
 	"github.com/wzt3309/k8sconsole/src/app/backend/client"
 	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
 	"github.com/wzt3309/k8sconsole/src/app/backend/handler"
+	"github.com/wzt3309/k8sconsole/src/app/backend/history"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
+	"io/ioutil"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"net"
 	"net/http"
 	"time"
@@ -23,15 +33,43 @@ var (
 	argInsecureBindAddress = pflag.IP("insecure-bind-address", net.IPv4(127, 0, 0, 1), "The IP address on which to serve the --insecure-port (set to 0.0.0.0 for all interfaces).")
 	argBindAddress         = pflag.IP("bind-address", net.IPv4(0, 0, 0, 0), "The IP address on which to serve the --port (set to 0.0.0.0 for all interfaces).")
 
+	argTLSCertFile              = pflag.String("tls-cert-file", "", "File containing the default x509 certificate for HTTPS. The file is watched and reloaded when changed.")
+	argTLSKeyFile               = pflag.String("tls-key-file", "", "File containing the default x509 private key matching --tls-cert-file.")
+	argAutoGenerateCertificates = pflag.Bool("auto-generate-certificates", false, "When set to true, k8sconsole will generate a self-signed certificate and store it in the '"+
+		certApi.CertHolderName+"' secret in the '"+certApi.CertHolderNamespace+"' namespace, so it is shared across replicas. Ignored if --tls-cert-file is set. Default: false.")
+
 	argApiServerHost = pflag.String("apiserver-host", "", "The address of kubernetes apiserver to connect to in the format of protocol://address:port, e.g. http://localhost:8080."+
 		"If not specified, the assumption is that k8sconsole binary runs inside a kubernetes cluster and local discovery is attempted")
 	argKubeConfigFile = pflag.String("kubeconfig", "", "Path to kubeconfig file with kubernetes cluster authorization and location information."+
 		"If not specified, the assumption is that k8sconsole binary runs inside a kubernetes cluster and local discovery is attempted")
-	argTokenTTL           = pflag.Int("token-ttl", int(authApi.DefaultTokenTTL), "Expiration time (in seconds) of JWE tokens generated by k8sconsole. Default: 15 min. 0 - never expires.")
-	argAuthenticationMode = pflag.StringSlice("authentication-mode", []string{authApi.Token.String()}, "Enables authentication options that will be reflected on login screen. Supported values: basic, token. Default: token."+
+	argClustersKubeConfig = pflag.String("clusters-kubeconfig", "", "Path to kubeconfig file with multiple contexts or directory of kubeconfig files. Every context is registered as "+
+		"an additional cluster that can be selected with '"+clientApi.ClusterHeader+"' header or '/api/v1/clusters/{cluster}' path prefix. Context names have to be unique.")
+	argTokenTTL               = pflag.Int("token-ttl", int(authApi.DefaultTokenTTL), "Expiration time (in seconds) of JWE tokens generated by k8sconsole. Default: 15 min. 0 - never expires.")
+	argTokenKeyRotationPeriod = pflag.Int("token-key-rotation-period", 0, "Period (in seconds) after which the JWE encryption key is rotated. Tokens encrypted with previous keys "+
+		"stay valid until the key is removed from the keyring. Default: 0 - never rotates.")
+	argTokenKeyRingSize      = pflag.Int("token-key-ring-size", jwe.DefaultKeyRingSize, "Number of the latest JWE encryption keys kept for token decryption when key rotation is enabled.")
+	argShareTokenRevocations = pflag.Bool("share-token-revocations", false, "When enabled, IDs of tokens revoked on logout are stored in the '"+
+		authApi.TokenRevocationListName+"' secret in the '"+authApi.EncryptionKeyHolderNamespace+"' namespace, so they are rejected by all replicas. Default: false.")
+	argAuthenticationMode = pflag.StringSlice("authentication-mode", []string{authApi.Token.String()}, "Enables authentication options that will be reflected on login screen. Supported values: basic, token, kubeconfig, oidc. Default: token."+
 		"Note that basic option should only be used if apiserver has '--authorization-mode=ABAC' and '--basic-auth-file' flags set.")
+	argOIDCIssuerURL       = pflag.String("oidc-issuer-url", "", "The URL of the OpenID Connect issuer used by 'oidc' authentication mode. It should be the same issuer apiserver is configured with.")
+	argOIDCClientID        = pflag.String("oidc-client-id", "", "The client ID of k8sconsole registered by the OpenID Connect issuer. ID tokens have to be accepted by apiserver.")
+	argOIDCClientSecret    = pflag.String("oidc-client-secret", "", "The client secret of k8sconsole registered by the OpenID Connect issuer.")
+	argOIDCRedirectURL     = pflag.String("oidc-redirect-url", "", "The URL of k8sconsole login page the OpenID Connect issuer redirects user to after authorization.")
+	argOIDCScopes          = pflag.StringSlice("oidc-scopes", []string{"openid", "email"}, "Scopes requested from the OpenID Connect issuer. Add 'offline_access' if the issuer requires it to return refresh token.")
+	argAuthProxyUserHeader = pflag.String("authentication-proxy-user-header", "", "Name of the request header set by authenticating proxy that contains username, e.g. X-Remote-User. "+
+		"When set, users authenticated by the proxy are impersonated on apiserver. Requires --authentication-proxy-trusted-cidrs or --authentication-proxy-client-ca-file.")
+	argAuthProxyGroupHeader  = pflag.String("authentication-proxy-group-header", "X-Remote-Group", "Name of the request header set by authenticating proxy that contains user groups.")
+	argAuthProxyTrustedCIDRs = pflag.StringSlice("authentication-proxy-trusted-cidrs", []string{}, "Networks (in CIDR notation) authenticating proxy requests are accepted from.")
+	argAuthProxyClientCAFile = pflag.String("authentication-proxy-client-ca-file", "", "File containing CA bundle used to verify client certificate of authenticating proxy. "+
+		"Requires k8sconsole to be served over HTTPS.")
 	argDisableSkip         = pflag.Bool("disable-skip", false, "When enabled, the skip button on the login page will not be shown. Default: false.")
 	argEnableInsecureLogin = pflag.Bool("enable-insecure-login", false, "When enabled, k8sconsole login view will also be shown when k8sconsole is not served over HTTPS. Default: false.")
+	argEnableResourceCache = pflag.Bool("enable-resource-cache", false, "When enabled, lists of frequently used resources are watched with the insecure client and served from memory "+
+		"after access of the user is checked with SelfSubjectAccessReview. Status of the cache is reported in '"+api.CacheStatusHeader+"' response header. Default: false.")
+	argHistoryBackend = pflag.String("history-backend", history.BackendMemory, "Where objects changed through k8sconsole are recorded, so the changes can be reverted. One of '"+history.BackendMemory+"', "+
+		"'"+history.BackendConfigMap+"' or '"+history.BackendSecret+"'. ConfigMap and Secret backends store the history of every namespace in a single ConfigMap or Secret in the namespace with permissions of k8sconsole service account. It is not accessible through k8sconsole API. Secret data is redacted in the ConfigMap backend. Empty value disables the history.")
+	argHistorySize = pflag.Int("history-size", 10, "Maximal number of revisions recorded for a single object.")
 )
 
 func initArgHolder() {
@@ -40,19 +78,50 @@ func initArgHolder() {
 	builder.SetPort(*argPort)
 	builder.SetInsecureBindAddress(*argInsecureBindAddress)
 	builder.SetBindAddress(*argBindAddress)
+	builder.SetTLSCertFile(*argTLSCertFile)
+	builder.SetTLSKeyFile(*argTLSKeyFile)
+	builder.SetAutoGenerateCertificates(*argAutoGenerateCertificates)
 	builder.SetApiServerHost(*argApiServerHost)
 	builder.SetKubeConfigFile(*argKubeConfigFile)
+	builder.SetClustersKubeConfigPath(*argClustersKubeConfig)
 	builder.SetTokenTTL(*argTokenTTL)
+	builder.SetTokenKeyRotationPeriod(*argTokenKeyRotationPeriod)
+	builder.SetTokenKeyRingSize(*argTokenKeyRingSize)
+	builder.SetShareTokenRevocations(*argShareTokenRevocations)
 	builder.SetAuthenticationMode(*argAuthenticationMode)
 	builder.SetDisableSkipButton(*argDisableSkip)
+	builder.SetOIDCIssuerURL(*argOIDCIssuerURL)
+	builder.SetOIDCClientID(*argOIDCClientID)
+	builder.SetOIDCClientSecret(*argOIDCClientSecret)
+	builder.SetOIDCRedirectURL(*argOIDCRedirectURL)
+	builder.SetOIDCScopes(*argOIDCScopes)
+	builder.SetAuthProxyUserHeader(*argAuthProxyUserHeader)
+	builder.SetAuthProxyGroupHeader(*argAuthProxyGroupHeader)
+	builder.SetAuthProxyTrustedCIDRs(*argAuthProxyTrustedCIDRs)
+	builder.SetAuthProxyClientCAFile(*argAuthProxyClientCAFile)
 	builder.SetEnableInsecureLogin(*argEnableInsecureLogin)
+	builder.SetEnableResourceCache(*argEnableResourceCache)
+	builder.SetHistoryBackend(*argHistoryBackend)
+	builder.SetHistorySize(*argHistorySize)
 }
 
 func initAuthManager(clientManager clientApi.ClientManager) authApi.AuthManager {
 	// Init tokenManager
-	keyHolder := jwe.NewRSAKeyHolder()
-	tokenManager := jwe.NewJWETokenManager(keyHolder)
+	rotationPeriod := time.Duration(args.Holder.GetTokenKeyRotationPeriod()) * time.Second
+	keyRingSize := args.Holder.GetTokenKeyRingSize()
 	tokenTTL := time.Duration(args.Holder.GetTokenTTL())
+	if rotationPeriod > 0 && (tokenTTL == 0 || time.Duration(keyRingSize-1)*rotationPeriod < tokenTTL*time.Second) {
+		glog.Warning("JWE encryption keys are removed from the keyring before tokens expire, " +
+			"active sessions may be logged out after key rotation")
+	}
+
+	keyHolder := jwe.NewRSAKeyHolder(clientManager.InsecureClient(), rotationPeriod, keyRingSize)
+	revocations := jwe.NewRevocationList()
+	if args.Holder.GetShareTokenRevocations() {
+		revocations = jwe.NewSecretRevocationList(clientManager.InsecureClient())
+	}
+
+	tokenManager := jwe.NewJWETokenManager(keyHolder, revocations)
 	if tokenTTL != authApi.DefaultTokenTTL {
 		tokenManager.SetTokenTTL(tokenTTL)
 	}
@@ -68,7 +137,101 @@ func initAuthManager(clientManager clientApi.ClientManager) authApi.AuthManager
 
 	authenticationSkippable := !args.Holder.GetDisableSkipButton()
 
-	return auth.NewAuthManager(clientManager, tokenManager, authModes, authenticationSkippable)
+	return auth.NewAuthManager(clientManager, tokenManager, authModes, authenticationSkippable,
+		initOIDCProvider(authModes))
+}
+
+// Returns OpenID Connect provider if 'oidc' authentication mode is enabled, nil otherwise.
+func initOIDCProvider(authModes authApi.AuthenticationModes) *oidc.Provider {
+	if !authModes.IsEnabled(authApi.OIDC) {
+		return nil
+	}
+
+	if len(args.Holder.GetOIDCIssuerURL()) == 0 || len(args.Holder.GetOIDCClientID()) == 0 {
+		glog.Fatal("Both --oidc-issuer-url and --oidc-client-id have to be set to enable oidc authentication mode")
+	}
+
+	return oidc.NewProvider(oidc.Config{
+		IssuerURL:    args.Holder.GetOIDCIssuerURL(),
+		ClientID:     args.Holder.GetOIDCClientID(),
+		ClientSecret: args.Holder.GetOIDCClientSecret(),
+		RedirectURL:  args.Holder.GetOIDCRedirectURL(),
+		Scopes:       args.Holder.GetOIDCScopes(),
+	})
+}
+
+// Configures authenticating proxy of client manager based on provided arguments. Returns pool of CAs used
+// to verify proxy client certificates or nil if proxy is not configured to use them.
+func initAuthProxy(clientManager clientApi.ClientManager) *x509.CertPool {
+	if len(args.Holder.GetAuthProxyUserHeader()) == 0 {
+		return nil
+	}
+
+	config := &clientApi.AuthProxyConfig{
+		UserHeader:   args.Holder.GetAuthProxyUserHeader(),
+		GroupHeader:  args.Holder.GetAuthProxyGroupHeader(),
+		TrustedCIDRs: make([]*net.IPNet, 0),
+	}
+
+	for _, cidr := range args.Holder.GetAuthProxyTrustedCIDRs() {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			glog.Fatalf("Invalid authenticating proxy CIDR %s: %s", cidr, err.Error())
+		}
+		config.TrustedCIDRs = append(config.TrustedCIDRs, ipNet)
+	}
+
+	if len(args.Holder.GetAuthProxyClientCAFile()) > 0 {
+		caPEM, err := ioutil.ReadFile(args.Holder.GetAuthProxyClientCAFile())
+		if err != nil {
+			glog.Fatalf("Error while reading authenticating proxy CA file: %s", err.Error())
+		}
+
+		config.ClientCA = x509.NewCertPool()
+		if !config.ClientCA.AppendCertsFromPEM(caPEM) {
+			glog.Fatalf("No certificates found in authenticating proxy CA file %s",
+				args.Holder.GetAuthProxyClientCAFile())
+		}
+	}
+
+	if len(config.TrustedCIDRs) == 0 && config.ClientCA == nil {
+		glog.Fatal("Either --authentication-proxy-trusted-cidrs or --authentication-proxy-client-ca-file " +
+			"has to be set to enable authenticating proxy")
+	}
+
+	glog.Infof("Using authenticating proxy identity from %s header", config.UserHeader)
+	clientManager.SetAuthProxyConfig(config)
+	return config.ClientCA
+}
+
+// Returns certificate manager based on provided arguments. If neither certificate files nor auto-generation
+// are set then nil is returned and k8sconsole is served only over HTTP. Certificate file without key file and vice
+// versa is a fatal error, so k8sconsole is never served over HTTP by mistake.
+func initCertManager(clientManager clientApi.ClientManager) certApi.Manager {
+	var source certApi.Source
+	switch {
+	case len(args.Holder.GetTLSCertFile()) > 0 && len(args.Holder.GetTLSKeyFile()) == 0:
+		glog.Fatal("Flag --tls-key-file is required when --tls-cert-file is set")
+	case len(args.Holder.GetTLSCertFile()) == 0 && len(args.Holder.GetTLSKeyFile()) > 0:
+		glog.Fatal("Flag --tls-cert-file is required when --tls-key-file is set")
+	case len(args.Holder.GetTLSCertFile()) > 0 && len(args.Holder.GetTLSKeyFile()) > 0:
+		glog.Infof("Using TLS certificate file: %s", args.Holder.GetTLSCertFile())
+		source = cert.NewFileSource(args.Holder.GetTLSCertFile(), args.Holder.GetTLSKeyFile())
+	case args.Holder.GetAutoGenerateCertificates():
+		glog.Infof("Using auto-generated certificate stored in %s/%s secret",
+			certApi.CertHolderNamespace, certApi.CertHolderName)
+		source = cert.NewSecretSource(clientManager.InsecureClient())
+	default:
+		return nil
+	}
+
+	certManager, err := cert.NewCertManager(source)
+	if err != nil {
+		glog.Fatalf("Error while loading TLS certificate: %s", err.Error())
+	}
+
+	go certManager.Run(certApi.DefaultReloadPeriod, wait.NeverStop)
+	return certManager
 }
 
 func handleFatalInitError(err error) {
@@ -103,8 +266,36 @@ func main() {
 
 	glog.Infof("Successful initial request to the apiserver, version: %s", versionInfo.String())
 
+	if path := args.Holder.GetClustersKubeConfigPath(); len(path) > 0 {
+		clusters, err := client.LoadClusters(path)
+		if err != nil {
+			glog.Fatalf("Error while loading clusters: %s", err.Error())
+		}
+
+		glog.Infof("Registered %d additional clusters from %s", len(clusters), path)
+		clientManager.SetClusters(clusters)
+	}
+
+	if args.Holder.GetEnableResourceCache() {
+		resourceCache := common.NewResourceCache(clientManager.InsecureClient())
+		resourceCache.Run(wait.NeverStop)
+		common.SetResourceCache(resourceCache)
+		glog.Info("Serving resource lists from cache")
+	}
+
+	if name := args.Holder.GetHistoryBackend(); len(name) > 0 && args.Holder.GetHistorySize() > 0 {
+		backend, err := history.NewBackend(name)
+		if err != nil {
+			glog.Fatalf("Error while initializing history: %s", err.Error())
+		}
+
+		history.SetRecorder(history.NewRecorder(backend, args.Holder.GetHistorySize()))
+		glog.Infof("Recording history of changes in %s backend", name)
+	}
+
 	// Initialize auth manager
 	authManager := initAuthManager(clientManager)
+	proxyClientCA := initAuthProxy(clientManager)
 
 	// Create apiHandler
 	apiHandler, err := handler.CreateHTTPAPIHandler(clientManager, authManager)
@@ -115,9 +306,23 @@ func main() {
 	http.Handle("/api/", apiHandler)
 	http.Handle("/api/sockjs/", handler.CreateAttachHandler("/api/sockjs"))
 
-	// TODO(wzt3309) listening on https
+	if certManager := initCertManager(clientManager); certManager != nil {
+		glog.Infof("Serving securely on HTTPS port: %d", args.Holder.GetPort())
+		secureAddr := fmt.Sprintf("%s:%d", args.Holder.GetBindAddress(), args.Holder.GetPort())
+		server := &http.Server{
+			Addr:      secureAddr,
+			Handler:   http.DefaultServeMux,
+			TLSConfig: &tls.Config{GetCertificate: certManager.GetCertificate},
+		}
+		if proxyClientCA != nil {
+			server.TLSConfig.ClientCAs = proxyClientCA
+			server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		go func() { glog.Fatal(server.ListenAndServeTLS("", "")) }()
+	}
+
 	glog.Infof("Serving insecurely on HTTP port: %d", args.Holder.GetInsecurePort())
 	addr := fmt.Sprintf("%s:%d", args.Holder.GetInsecureBindAddress(), args.Holder.GetInsecurePort())
-	go func() {glog.Fatal(http.ListenAndServe(addr, nil))}()
+	go func() { glog.Fatal(http.ListenAndServe(addr, nil)) }()
 	select {}
 }
@@ -0,0 +1,287 @@
+package dryrun
+
+import (
+	"fmt"
+	"github.com/ghodss/yaml"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Number of unchanged lines shown around changes in unified diff.
+const diffContextLines = 3
+
+// Maximum size of the longest common subsequence table of changed lines. Objects with more changed lines are
+// compared field by field only, as the table takes quadratic memory.
+const maxDiffCells = 1 << 20
+
+// Operations of changes, named after JSON Patch operations.
+const (
+	OperationAdd     = "add"
+	OperationRemove  = "remove"
+	OperationReplace = "replace"
+)
+
+// Change of a single field.
+type Change struct {
+	// Path is JSON Pointer to the changed field, i.e. '/spec/replicas'.
+	Path      string      `json:"path"`
+	Operation string      `json:"operation"`
+	Old       interface{} `json:"old,omitempty"`
+	New       interface{} `json:"new,omitempty"`
+}
+
+// Appends changes between old and new value at given path. Maps are compared key by key and lists item
+// by item, other values as a whole.
+func compare(path string, old, new interface{}, changes []Change) []Change {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0)
+		for key := range oldMap {
+			keys = append(keys, key)
+		}
+		for key := range newMap {
+			if _, ok := oldMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			changes = compareField(path+"/"+escapePointer(key), oldMap, newMap, key, changes)
+		}
+
+		return changes
+	}
+
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+	if oldIsList && newIsList {
+		for i := 0; i < len(oldList) || i < len(newList); i++ {
+			itemPath := fmt.Sprintf("%s/%d", path, i)
+			switch {
+			case i >= len(newList):
+				changes = append(changes, Change{Path: itemPath, Operation: OperationRemove, Old: oldList[i]})
+			case i >= len(oldList):
+				changes = append(changes, Change{Path: itemPath, Operation: OperationAdd, New: newList[i]})
+			default:
+				changes = compare(itemPath, oldList[i], newList[i], changes)
+			}
+		}
+
+		return changes
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		changes = append(changes, Change{Path: path, Operation: OperationReplace, Old: old, New: new})
+	}
+
+	return changes
+}
+
+func compareField(path string, old, new map[string]interface{}, key string, changes []Change) []Change {
+	oldValue, inOld := old[key]
+	newValue, inNew := new[key]
+	switch {
+	case !inNew:
+		return append(changes, Change{Path: path, Operation: OperationRemove, Old: oldValue})
+	case !inOld:
+		return append(changes, Change{Path: path, Operation: OperationAdd, New: newValue})
+	default:
+		return compare(path, oldValue, newValue, changes)
+	}
+}
+
+// Escapes JSON Pointer reference token as described in RFC 6901.
+func escapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// Returns unified diff of YAML representations of both objects. Empty objects are represented by no lines.
+// False is returned if the objects have too many changed lines to be diffed.
+func unifiedDiff(old, new map[string]interface{}) (string, bool, error) {
+	oldLines, err := yamlLines(old)
+	if err != nil {
+		return "", false, err
+	}
+
+	newLines, err := yamlLines(new)
+	if err != nil {
+		return "", false, err
+	}
+
+	edits, ok := diffLines(oldLines, newLines)
+	if !ok {
+		return "", false, nil
+	}
+
+	result := make([]string, 0)
+	for _, hunk := range hunks(edits) {
+		result = append(result, hunk...)
+	}
+
+	if len(result) == 0 {
+		return "", true, nil
+	}
+
+	return "--- live\n+++ dry-run\n" + strings.Join(result, "\n") + "\n", true, nil
+}
+
+func yamlLines(object map[string]interface{}) ([]string, error) {
+	if len(object) == 0 {
+		return []string{}, nil
+	}
+
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// Single line of the diff. Kind is ' ' for unchanged, '-' for removed and '+' for added line.
+type lineEdit struct {
+	kind byte
+	line string
+	// Line numbers, starting with 1, in old and new lines after the edit is applied.
+	oldLine int
+	newLine int
+}
+
+// Computes line edits using longest common subsequence of both line lists. Common leading and trailing lines
+// are matched first, so only changed lines in between are compared. False is returned if there are too many
+// of them.
+func diffLines(old, new []string) ([]lineEdit, bool) {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+
+	oldChanged, newChanged := old[prefix:len(old)-suffix], new[prefix:len(new)-suffix]
+	if (len(oldChanged)+1)*(len(newChanged)+1) > maxDiffCells {
+		return nil, false
+	}
+
+	lcs := make([][]int, len(oldChanged)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newChanged)+1)
+	}
+
+	for i := len(oldChanged) - 1; i >= 0; i-- {
+		for j := len(newChanged) - 1; j >= 0; j-- {
+			if oldChanged[i] == newChanged[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	edits := make([]lineEdit, 0)
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, lineEdit{' ', old[i], i + 1, i + 1})
+	}
+
+	i, j := 0, 0
+	for i < len(oldChanged) || j < len(newChanged) {
+		switch {
+		case i < len(oldChanged) && j < len(newChanged) && oldChanged[i] == newChanged[j]:
+			i, j = i+1, j+1
+			edits = append(edits, lineEdit{' ', oldChanged[i-1], prefix + i, prefix + j})
+		case i < len(oldChanged) && (j == len(newChanged) || lcs[i+1][j] >= lcs[i][j+1]):
+			i++
+			edits = append(edits, lineEdit{'-', oldChanged[i-1], prefix + i, prefix + j})
+		default:
+			j++
+			edits = append(edits, lineEdit{'+', newChanged[j-1], prefix + i, prefix + j})
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		i, j := len(old)-suffix+k, len(new)-suffix+k
+		edits = append(edits, lineEdit{' ', old[i], i + 1, j + 1})
+	}
+
+	return edits, true
+}
+
+// Groups changed lines with surrounding context into unified diff hunks.
+func hunks(edits []lineEdit) [][]string {
+	result := make([][]string, 0)
+	for start := 0; start < len(edits); {
+		if edits[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// Extend the hunk while the next change is close enough to share context.
+		from := max(0, start-diffContextLines)
+		end := start
+		for i := start; i < len(edits) && i <= end+2*diffContextLines; i++ {
+			if edits[i].kind != ' ' {
+				end = i
+			}
+		}
+		to := min(len(edits), end+diffContextLines+1)
+
+		oldStart, newStart := edits[from].oldLine, edits[from].newLine
+		if edits[from].kind != '+' {
+			oldStart--
+		}
+		if edits[from].kind != '-' {
+			newStart--
+		}
+
+		oldCount, newCount := 0, 0
+		lines := make([]string, 0)
+		for _, edit := range edits[from:to] {
+			if edit.kind != '+' {
+				oldCount++
+			}
+			if edit.kind != '-' {
+				newCount++
+			}
+			lines = append(lines, string(edit.kind)+edit.line)
+		}
+
+		header := fmt.Sprintf("@@ -%s +%s @@", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		result = append(result, append([]string{header}, lines...))
+		start = to
+	}
+
+	return result
+}
+
+// Formats hunk range. Start is the number of lines preceding the hunk, so it is incremented unless
+// the range is empty.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
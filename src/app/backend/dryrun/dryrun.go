@@ -0,0 +1,96 @@
+// Package dryrun describes results of requests sent to apiserver with 'dryRun=All' and computes what
+// they would change in the live object.
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"strconv"
+	"strings"
+)
+
+// DryRunParam is the query parameter and value that make apiserver run the request without persisting it.
+const (
+	DryRunParam = "dryRun"
+	DryRunAll   = "All"
+)
+
+// Minimal apiserver version that supports dry-run. Older apiservers ignore the parameter and persist the request.
+const (
+	minDryRunMajor = 1
+	minDryRunMinor = 13
+)
+
+// Result describes what the request would change if it was sent without dry-run.
+type Result struct {
+	// Object as it would be persisted by apiserver, with defaults and admission changes applied.
+	Object json.RawMessage `json:"object"`
+	// Changes compared to the live object. Status and metadata managed by apiserver are ignored.
+	Changes []Change `json:"changes"`
+	// Diff is the unified diff of YAML representation of the live object and the result.
+	Diff string `json:"diff"`
+	// DiffOmitted is true if the objects differ in too many lines to be diffed. Only Changes are set then.
+	DiffOmitted bool `json:"diffOmitted,omitempty"`
+}
+
+// NewResult compares the live object with the result of dry-run request. Live object is nil for creates.
+func NewResult(live []byte, result []byte) (*Result, error) {
+	liveObject, err := unmarshalObject(live)
+	if err != nil {
+		return nil, err
+	}
+
+	resultObject, err := unmarshalObject(result)
+	if err != nil {
+		return nil, err
+	}
+
+	unified, ok, err := unifiedDiff(liveObject, resultObject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Object:      json.RawMessage(result),
+		Changes:     compare("", liveObject, resultObject, make([]Change, 0)),
+		Diff:        unified,
+		DiffOmitted: !ok,
+	}, nil
+}
+
+// CheckSupported returns bad request error if apiserver does not support dry-run, as it would persist
+// the request instead.
+func CheckSupported(client discovery.ServerVersionInterface) error {
+	info, err := client.ServerVersion()
+	if err != nil {
+		return err
+	}
+
+	major, majorErr := strconv.Atoi(info.Major)
+	minor, minorErr := strconv.Atoi(strings.TrimSuffix(info.Minor, "+"))
+	if majorErr != nil || minorErr != nil || major < minDryRunMajor ||
+		(major == minDryRunMajor && minor < minDryRunMinor) {
+		return k8sErrors.NewBadRequest(fmt.Sprintf("Dry-run requires apiserver %d.%d or newer, but got %s.%s",
+			minDryRunMajor, minDryRunMinor, info.Major, info.Minor))
+	}
+
+	return nil
+}
+
+// Decodes object and removes fields that are not compared.
+func unmarshalObject(raw []byte) (map[string]interface{}, error) {
+	object := make(map[string]interface{})
+	if len(raw) == 0 {
+		return object, nil
+	}
+
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	api.RemoveServerPopulatedFields(object)
+	return object, nil
+}
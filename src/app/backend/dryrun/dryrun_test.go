@@ -0,0 +1,123 @@
+package dryrun
+
+import (
+	"fmt"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/fake"
+	core "k8s.io/client-go/testing"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewResult(t *testing.T) {
+	live := []byte(`{"kind":"Deployment","metadata":{"name":"web","resourceVersion":"1","labels":{"app":"web"}},
+		"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"web","image":"nginx:1.13"}]}}},
+		"status":{"replicas":1}}`)
+	result := []byte(`{"kind":"Deployment","metadata":{"name":"web","resourceVersion":"2","labels":{"app":"web","tier":"fe"}},
+		"spec":{"replicas":3,"template":{"spec":{"containers":[{"name":"web","image":"nginx:1.15"}]}}},
+		"status":{"replicas":3}}`)
+
+	actual, err := NewResult(live, result)
+	if err != nil {
+		t.Fatalf("NewResult(): Unexpected error: %s", err)
+	}
+
+	expectedChanges := []Change{
+		{Path: "/metadata/labels/tier", Operation: OperationAdd, New: "fe"},
+		{Path: "/spec/replicas", Operation: OperationReplace, Old: float64(1), New: float64(3)},
+		{Path: "/spec/template/spec/containers/0/image", Operation: OperationReplace, Old: "nginx:1.13",
+			New: "nginx:1.15"},
+	}
+	if !reflect.DeepEqual(actual.Changes, expectedChanges) {
+		t.Errorf("NewResult(): Expected changes %#v but got %#v", expectedChanges, actual.Changes)
+	}
+
+	expectedDiff := `--- live
++++ dry-run
+@@ -2,11 +2,12 @@
+ metadata:
+   labels:
+     app: web
++    tier: fe
+   name: web
+ spec:
+-  replicas: 1
++  replicas: 3
+   template:
+     spec:
+       containers:
+-      - image: nginx:1.13
++      - image: nginx:1.15
+         name: web
+`
+	if actual.Diff != expectedDiff {
+		t.Errorf("NewResult(): Expected diff:\n%s\nbut got:\n%s", expectedDiff, actual.Diff)
+	}
+
+	created, err := NewResult(nil, []byte(`{"kind":"ConfigMap","metadata":{"uid":"1"}}`))
+	if err != nil {
+		t.Fatalf("NewResult(): Unexpected error: %s", err)
+	}
+
+	if len(created.Changes) != 2 || created.Diff != "--- live\n+++ dry-run\n@@ -0,0 +1,2 @@\n+kind: ConfigMap\n+metadata: {}\n" {
+		t.Errorf("NewResult(): Unexpected result of create %#v", created)
+	}
+}
+
+func TestNewResult_LargeObject(t *testing.T) {
+	configMap := func(changed func(i int) bool) []byte {
+		data := make([]string, 0)
+		for i := 0; i < 2000; i++ {
+			value := "old"
+			if changed(i) {
+				value = "new"
+			}
+			data = append(data, fmt.Sprintf(`"key-%04d":"%s"`, i, value))
+		}
+		return []byte(`{"kind":"ConfigMap","data":{` + strings.Join(data, ",") + `}}`)
+	}
+	live := configMap(func(int) bool { return false })
+
+	one, err := NewResult(live, configMap(func(i int) bool { return i == 1000 }))
+	if err != nil {
+		t.Fatalf("NewResult(): Unexpected error: %s", err)
+	}
+
+	if one.DiffOmitted || !strings.Contains(one.Diff, "@@ -999,7 +999,7 @@") || len(one.Changes) != 1 {
+		t.Errorf("NewResult(): Expected diff of single changed line but got %q %v", one.Diff, one.Changes)
+	}
+
+	all, err := NewResult(live, configMap(func(int) bool { return true }))
+	if err != nil {
+		t.Fatalf("NewResult(): Unexpected error: %s", err)
+	}
+
+	if !all.DiffOmitted || len(all.Diff) != 0 || len(all.Changes) != 2000 {
+		t.Errorf("NewResult(): Expected diff to be omitted but got omitted %v, %d changes", all.DiffOmitted,
+			len(all.Changes))
+	}
+}
+
+func TestCheckSupported(t *testing.T) {
+	cases := []struct {
+		major, minor string
+		expectedErr  bool
+	}{
+		{"1", "9", true},
+		{"1", "13", false},
+		{"1", "14+", false},
+		{"", "", true},
+	}
+
+	for _, c := range cases {
+		client := &fake.FakeDiscovery{Fake: &core.Fake{},
+			FakedServerVersion: &version.Info{Major: c.major, Minor: c.minor}}
+		err := CheckSupported(client)
+		if (err != nil) != c.expectedErr || (err != nil && !k8sErrors.IsBadRequest(err)) {
+			t.Errorf("CheckSupported(%s.%s): Expected error to be %t but got %v", c.major, c.minor,
+				c.expectedErr, err)
+		}
+	}
+}
@@ -8,7 +8,10 @@ import (
 )
 
 const (
-	MSG_TOKEN_EXPIRED_ERROR = "MSG_TOKEN_EXPIRED_ERROR"
+	MSG_TOKEN_EXPIRED_ERROR                 = "MSG_TOKEN_EXPIRED_ERROR"
+	MSG_TOKEN_REVOKED_ERROR                 = "MSG_TOKEN_REVOKED_ERROR"
+	MSG_K8SCONSOLE_EXCLUSIVE_RESOURCE_ERROR = "MSG_K8SCONSOLE_EXCLUSIVE_RESOURCE_ERROR"
+	MSG_CSRF_VALIDATION_ERROR               = "MSG_CSRF_VALIDATION_ERROR"
 )
 
 // NonCriticalErrors is an slice of error statuses which are non-critical
@@ -98,7 +101,7 @@ func HandleHTTPError(err error) int {
 		return http.StatusInternalServerError
 	}
 
-	if err.Error() == MSG_TOKEN_EXPIRED_ERROR {
+	if err.Error() == MSG_TOKEN_EXPIRED_ERROR || err.Error() == MSG_TOKEN_REVOKED_ERROR {
 		return http.StatusUnauthorized
 	}
 
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/access"
 	"github.com/wzt3309/k8sconsole/src/app/backend/api"
 	"github.com/wzt3309/k8sconsole/src/app/backend/auth"
 	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"github.com/wzt3309/k8sconsole/src/app/backend/bulk"
 	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
 	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
+	"github.com/wzt3309/k8sconsole/src/app/backend/history"
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/cluster"
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/config"
@@ -41,9 +45,15 @@ import (
 	"github.com/wzt3309/k8sconsole/src/app/backend/scale"
 	"github.com/wzt3309/k8sconsole/src/app/backend/validation"
 	"golang.org/x/net/xsrftoken"
+	"io/ioutil"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientcmdApi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/remotecommand"
 	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
@@ -63,7 +73,10 @@ func CreateHTTPAPIHandler(cManager clientApi.ClientManager, authManager authApi.
 	apiV1Ws := new(restful.WebService)
 	apiV1Ws.Path("/api/v1").
 		Consumes(restful.MIME_JSON).
-		Produces(restful.MIME_JSON)
+		Produces(restful.MIME_JSON).
+		Filter(restrictedResourcesFilter).
+		Filter(resourceCacheFilter).
+		Filter(csrfFilter("/api/v1", cManager.CSRFKey))
 	wsContainer.Add(apiV1Ws)
 
 	authHandler := auth.NewAuthHandler(authManager)
@@ -74,6 +87,25 @@ func CreateHTTPAPIHandler(cManager clientApi.ClientManager, authManager authApi.
 			To(apiHandler.handleGetCsrfToken).
 			Writes(api.CsrfToken{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/clusters").
+			To(apiHandler.handleGetClusters).
+			Writes(ClusterList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/me").
+			To(apiHandler.handleGetMe).
+			Writes(access.Me{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/me/{namespace}").
+			To(apiHandler.handleGetMe).
+			Writes(access.Me{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/cani").
+			To(apiHandler.handleCanI).
+			Reads(access.AccessCheckSpec{}).
+			Writes(access.AccessCheckResultList{}))
+
 	apiV1Ws.Route(
 		apiV1Ws.POST("/deploy").
 			To(apiHandler.handleDeploy).
@@ -445,25 +477,66 @@ func CreateHTTPAPIHandler(cManager clientApi.ClientManager, authManager authApi.
 			To(apiHandler.handleGetNodePods).
 			Writes(pod.PodList{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.POST("/_raw/{kind}/namespace/{namespace}").
+			To(apiHandler.handleCreateResource))
 	apiV1Ws.Route(
 		apiV1Ws.DELETE("/_raw/{kind}/namespace/{namespace}/name/{name}").
-			To(apiHandler.handleDeleteResource))
+			To(apiHandler.handleDeleteResource).
+			Writes(clientApi.DeletePreview{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/_raw/{kind}/namespace/{namespace}/name/{name}").
+			Produces(rawContentTypes...).
 			To(apiHandler.handleGetResource))
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/_raw/{kind}/namespace/{namespace}/name/{name}").
+			Consumes(rawContentTypes...).
+			Produces(rawContentTypes...).
 			To(apiHandler.handlePutResource))
+	apiV1Ws.Route(
+		apiV1Ws.PATCH("/_raw/{kind}/namespace/{namespace}/name/{name}").
+			Consumes(patchContentTypes...).
+			To(apiHandler.handlePatchResource))
 
+	apiV1Ws.Route(
+		apiV1Ws.POST("/_raw/{kind}").
+			To(apiHandler.handleCreateResource))
 	apiV1Ws.Route(
 		apiV1Ws.DELETE("/_raw/{kind}/name/{name}").
-			To(apiHandler.handleDeleteResource))
+			To(apiHandler.handleDeleteResource).
+			Writes(clientApi.DeletePreview{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/_raw/{kind}/name/{name}").
+			Produces(rawContentTypes...).
 			To(apiHandler.handleGetResource))
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/_raw/{kind}/name/{name}").
+			Consumes(rawContentTypes...).
+			Produces(rawContentTypes...).
 			To(apiHandler.handlePutResource))
+	apiV1Ws.Route(
+		apiV1Ws.PATCH("/_raw/{kind}/name/{name}").
+			Consumes(patchContentTypes...).
+			To(apiHandler.handlePatchResource))
+
+	apiV1Ws.Route(
+		apiV1Ws.POST("/bulk").
+			To(apiHandler.handleBulkAction).
+			Reads(bulk.Spec{}).
+			Writes(bulk.Result{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/bulk/preview").
+			To(apiHandler.handleBulkActionPreview).
+			Reads(bulk.Spec{}).
+			Writes(bulk.Result{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/history/{kind}/{namespace}/{name}").
+			To(apiHandler.handleGetHistory).
+			Writes(history.RevisionList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/history/{kind}/{namespace}/{name}/revert/{revision}").
+			To(apiHandler.handleRevert))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/rbac/role").
@@ -539,7 +612,7 @@ func CreateHTTPAPIHandler(cManager clientApi.ClientManager, authManager authApi.
 		apiV1Ws.GET("/overview/{namespace}").
 			To(apiHandler.handleOverview).
 			Writes(overview.Overview{}))
-	return wsContainer, nil
+	return clusterPrefixHandler("/api/v1", wsContainer), nil
 }
 
 func (apiHandler *APIHandler) handleGetCsrfToken(request *restful.Request, response *restful.Response) {
@@ -548,6 +621,81 @@ func (apiHandler *APIHandler) handleGetCsrfToken(request *restful.Request, respo
 	response.WriteHeaderAndEntity(http.StatusOK, api.CsrfToken{Token: token})
 }
 
+func (apiHandler *APIHandler) handleGetClusters(request *restful.Request, response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, ClusterList{Clusters: apiHandler.cManager.Clusters()})
+}
+
+func (apiHandler *APIHandler) handleGetMe(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	authInfo, err := apiHandler.authInfo(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	// Token is reviewed by the cluster it has been issued for.
+	authClient, err := apiHandler.cManager.InsecureClusterClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := access.GetMe(k8sClient, authClient, authInfo, namespace)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Returns auth info the request is sent to apiserver with. Nil is returned if the request has no credentials.
+func (apiHandler *APIHandler) authInfo(request *restful.Request) (*clientcmdApi.AuthInfo, error) {
+	cmdConfig, err := apiHandler.cManager.ClientCmdConfig(request)
+	if err != nil {
+		return nil, err
+	}
+
+	rawConfig, err := cmdConfig.RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if context, exists := rawConfig.Contexts[rawConfig.CurrentContext]; exists {
+		return rawConfig.AuthInfos[context.AuthInfo], nil
+	}
+
+	return nil, nil
+}
+
+func (apiHandler *APIHandler) handleCanI(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(access.AccessCheckSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		kcErrors.HandleInternalError(response, k8sErrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	result, err := access.CanI(k8sClient, spec)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleDeploy(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -580,7 +728,27 @@ func (apiHandler *APIHandler) handleDeployFromFile(request *restful.Request, res
 		return
 	}
 
-	isDeployed, err := deployment.DeployAppFromFile(cfg, deploymentSpec)
+	if deploymentSpec.DryRun {
+		results, err := deployment.DryRunAppFromFile(cfg, deploymentSpec)
+		if err != nil {
+			kcErrors.HandleInternalError(response, err)
+			return
+		}
+
+		response.WriteHeaderAndEntity(http.StatusOK, deployment.AppDeploymentFromFileResponse{
+			Name:          deploymentSpec.Name,
+			Content:       deploymentSpec.Content,
+			DryRunResults: results,
+		})
+		return
+	}
+
+	isDeployed, err := deployment.DeployAppFromFile(cfg, deploymentSpec,
+		func(resource *metaV1.APIResource, object runtime.Object) {
+			if resource.Namespaced {
+				apiHandler.recordRevision(request, history.OperationCreate, resource.Name, nil, object)
+			}
+		})
 	if !isDeployed {
 		kcErrors.HandleInternalError(response, err)
 		return
@@ -656,14 +824,18 @@ func (apiHandler *APIHandler) handleGetReplicationControllerList(request *restfu
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := replicationcontroller.GetReplicationControllerList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "replicationcontrollers")
 }
 
 func (apiHandler *APIHandler) handleGetReplicationControllerDetail(request *restful.Request, response *restful.Response) {
@@ -716,13 +888,17 @@ func (apiHandler *APIHandler) handleGetReplicationControllerPods(request *restfu
 
 	namespace := request.PathParameter("namespace")
 	rc := request.PathParameter("replicationController")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := replicationcontroller.GetReplicationControllerPods(k8sClient, dataSelect, rc, namespace)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetReplicationControllerEvents(request *restful.Request, response *restful.Response) {
@@ -734,13 +910,17 @@ func (apiHandler *APIHandler) handleGetReplicationControllerEvents(request *rest
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("replicationController")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetReplicationControllerServices(request *restful.Request,
@@ -753,13 +933,17 @@ func (apiHandler *APIHandler) handleGetReplicationControllerServices(request *re
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("replicationController")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := replicationcontroller.GetReplicationControllerServices(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "services")
 }
 
 func (apiHandler *APIHandler) handleGetWorkloads(request *restful.Request, response *restful.Response) {
@@ -770,7 +954,11 @@ func (apiHandler *APIHandler) handleGetWorkloads(request *restful.Request, respo
 	}
 
 	nsQuery := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := workload.GetWorkloads(k8sClient, nsQuery, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -787,7 +975,11 @@ func (apiHandler *APIHandler) handleGetDiscovery(request *restful.Request, respo
 	}
 
 	nsQuery := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := discovery.GetDiscovery(k8sClient, nsQuery, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -804,7 +996,11 @@ func (apiHandler *APIHandler) handleGetConfig(request *restful.Request, response
 	}
 
 	nsQuery := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := config.GetConfig(k8sClient, nsQuery, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -820,7 +1016,11 @@ func (apiHandler *APIHandler) handleGetCluster(request *restful.Request, respons
 		return
 	}
 
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := cluster.GetCluster(k8sClient, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -838,14 +1038,18 @@ func (apiHandler *APIHandler) handleGetReplicaSets(request *restful.Request, res
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := replicaset.GetReplicaSetList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "replicasets")
 }
 
 func (apiHandler *APIHandler) handleGetReplicaSetDetail(request *restful.Request, response *restful.Response) {
@@ -875,14 +1079,18 @@ func (apiHandler *APIHandler) handleGetReplicaSetPods(request *restful.Request,
 
 	namespace := request.PathParameter("namespace")
 	replicaSet := request.PathParameter("replicaSet")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := replicaset.GetReplicaSetPods(k8sClient, dsQuery, replicaSet, namespace)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetReplicaSetServices(request *restful.Request, response *restful.Response) {
@@ -894,14 +1102,18 @@ func (apiHandler *APIHandler) handleGetReplicaSetServices(request *restful.Reque
 
 	namespace := request.PathParameter("namespace")
 	replicaSet := request.PathParameter("replicaSet")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := replicaset.GetReplicaSetServices(k8sClient, dsQuery, namespace, replicaSet)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "services")
 }
 
 func (apiHandler *APIHandler) handleGetReplicaSetEvents(request *restful.Request, response *restful.Response) {
@@ -913,14 +1125,18 @@ func (apiHandler *APIHandler) handleGetReplicaSetEvents(request *restful.Request
 
 	namespace := request.PathParameter("namespace")
 	replicaSet := request.PathParameter("replicaSet")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetResourceEvents(k8sClient, dsQuery, namespace, replicaSet)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *restful.Response) {
@@ -931,14 +1147,18 @@ func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := pod.GetPodList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetPodDetail(request *restful.Request, response *restful.Response) {
@@ -986,13 +1206,17 @@ func (apiHandler *APIHandler) handleGetPodEvents(request *restful.Request, respo
 	log.Println("Getting events related to a pod in namespace")
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("pod")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := pod.GetEventsForPod(k8sClient, dsQuery, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "events")
 }
 
 func (apiHandler *APIHandler) handleExecShell(request *restful.Request, response *restful.Response) {
@@ -1034,14 +1258,18 @@ func (apiHandler *APIHandler) handleGetPodPersistentVolumeClaims(request *restfu
 
 	name := request.PathParameter("pod")
 	namespace := request.PathParameter("namespace")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := persistentvolumeclaim.GetPodPersistentVolumeClaims(k8sClient,
 		namespace, name, dataSelect)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "persistentvolumeclaims")
 }
 
 func (apiHandler *APIHandler) handleGetDeployments(request *restful.Request, response *restful.Response) {
@@ -1052,13 +1280,17 @@ func (apiHandler *APIHandler) handleGetDeployments(request *restful.Request, res
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := deployment.GetDeploymentList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "deployments")
 }
 
 func (apiHandler *APIHandler) handleGetDeploymentDetail(request *restful.Request, response *restful.Response) {
@@ -1088,13 +1320,17 @@ func (apiHandler *APIHandler) handleGetDeploymentEvents(request *restful.Request
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("deployment")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful.Request, response *restful.Response) {
@@ -1106,13 +1342,17 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("deployment")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := deployment.GetDeploymentOldReplicaSets(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "replicasets")
 }
 
 func (apiHandler *APIHandler) handleScaleResource(request *restful.Request, response *restful.Response) {
@@ -1122,15 +1362,27 @@ func (apiHandler *APIHandler) handleScaleResource(request *restful.Request, resp
 		return
 	}
 
+	verber, err := apiHandler.cManager.VerberClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
 	namespace := request.PathParameter("namespace")
 	kind := request.PathParameter("kind")
 	name := request.PathParameter("name")
 	count := request.QueryParameter("scaleBy")
+	before := apiHandler.recordedObject(verber, kind, true, namespace, name)
 	replicaCountSpec, err := scale.ScaleResource(k8sClient, kind, namespace, name, count)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
+
+	if before != nil {
+		apiHandler.recordRevision(request, history.OperationScale, kind, before,
+			apiHandler.recordedObject(verber, kind, true, namespace, name))
+	}
 	response.WriteHeaderAndEntity(http.StatusOK, replicaCountSpec)
 }
 
@@ -1160,13 +1412,17 @@ func (apiHandler *APIHandler) handleGetDaemonSetList(request *restful.Request, r
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := daemonset.GetDaemonSetList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "daemonsets")
 }
 
 func (apiHandler *APIHandler) handleGetDaemonSetDetail(
@@ -1196,13 +1452,17 @@ func (apiHandler *APIHandler) handleGetDaemonSetPods(request *restful.Request, r
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("daemonSet")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := daemonset.GetDaemonSetPods(k8sClient, dsQuery, name, namespace)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetDaemonSetServices(request *restful.Request, response *restful.Response) {
@@ -1214,13 +1474,17 @@ func (apiHandler *APIHandler) handleGetDaemonSetServices(request *restful.Reques
 
 	namespace := request.PathParameter("namespace")
 	daemonSet := request.PathParameter("daemonSet")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := daemonset.GetDaemonSetServices(k8sClient, dsQuery, namespace, daemonSet)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "services")
 }
 
 func (apiHandler *APIHandler) handleGetDaemonSetEvents(request *restful.Request, response *restful.Response) {
@@ -1232,13 +1496,17 @@ func (apiHandler *APIHandler) handleGetDaemonSetEvents(request *restful.Request,
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("daemonSet")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetResourceEvents(k8sClient, dsQuery, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(request *restful.Request,
@@ -1250,12 +1518,17 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(request *rest
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	result, err := hpa.GetHorizontalPodAutoscalerList(k8sClient, namespace)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	result, err := hpa.GetHorizontalPodAutoscalerList(k8sClient, namespace, dsQuery)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+	writeList(response, dsQuery, result, "horizontalpodautoscalers")
 }
 
 func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerDetail(request *restful.Request, response *restful.Response) {
@@ -1283,13 +1556,17 @@ func (apiHandler *APIHandler) handleGetJobList(request *restful.Request, respons
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := job.GetJobList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "jobs")
 }
 
 func (apiHandler *APIHandler) handleGetJobDetail(request *restful.Request, response *restful.Response) {
@@ -1318,13 +1595,17 @@ func (apiHandler *APIHandler) handleGetJobPods(request *restful.Request, respons
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := job.GetJobPods(k8sClient, dsQuery, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetJobEvents(request *restful.Request, response *restful.Response) {
@@ -1336,13 +1617,17 @@ func (apiHandler *APIHandler) handleGetJobEvents(request *restful.Request, respo
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := job.GetJobEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetCronJobList(request *restful.Request, response *restful.Response) {
@@ -1353,13 +1638,17 @@ func (apiHandler *APIHandler) handleGetCronJobList(request *restful.Request, res
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := cronjob.GetCronJobList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "cronjobs")
 }
 
 func (apiHandler *APIHandler) handleGetCronJobDetail(request *restful.Request, response *restful.Response) {
@@ -1371,7 +1660,11 @@ func (apiHandler *APIHandler) handleGetCronJobDetail(request *restful.Request, r
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := cronjob.GetCronJobDetail(k8sClient, dsQuery, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -1389,13 +1682,17 @@ func (apiHandler *APIHandler) handleGetCronJobJobs(request *restful.Request, res
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := cronjob.GetCronJobJobs(k8sClient, dsQuery, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "jobs")
 }
 
 func (apiHandler *APIHandler) handleGetCronJobEvents(request *restful.Request, response *restful.Response) {
@@ -1407,13 +1704,17 @@ func (apiHandler *APIHandler) handleGetCronJobEvents(request *restful.Request, r
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := cronjob.GetCronJobEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "events")
 }
 
 func (apiHandler *APIHandler) handleTriggerCronJob(request *restful.Request, response *restful.Response) {
@@ -1425,11 +1726,13 @@ func (apiHandler *APIHandler) handleTriggerCronJob(request *restful.Request, res
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	err = cronjob.TriggerCronJob(k8sClient, namespace, name)
+	job, err := cronjob.TriggerCronJob(k8sClient, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
+
+	apiHandler.recordRevision(request, history.OperationCreate, "job", nil, job)
 	response.WriteHeader(http.StatusOK)
 }
 
@@ -1460,14 +1763,18 @@ func (apiHandler *APIHandler) handleGetNamespaces(request *restful.Request, resp
 		return
 	}
 
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := ns.GetNamespaceList(k8sClient, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "namespaces")
 }
 
 func (apiHandler *APIHandler) handleGetNamespaceDetail(request *restful.Request, response *restful.Response) {
@@ -1494,14 +1801,18 @@ func (apiHandler *APIHandler) handleGetNodeList(request *restful.Request, respon
 		return
 	}
 
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := node.GetNodeList(k8sclient, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "nodes")
 }
 
 func (apiHandler *APIHandler) handleGetNodeDetail(request *restful.Request, response *restful.Response) {
@@ -1512,7 +1823,11 @@ func (apiHandler *APIHandler) handleGetNodeDetail(request *restful.Request, resp
 	}
 
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := node.GetNodeDetail(k8sClient, name, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -1530,14 +1845,18 @@ func (apiHandler *APIHandler) handleGetNamespaceEvents(request *restful.Request,
 	}
 
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetNamespaceEvents(k8sClient, dsQuery, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetSecretList(request *restful.Request, response *restful.Response) {
@@ -1548,14 +1867,18 @@ func (apiHandler *APIHandler) handleGetSecretList(request *restful.Request, resp
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := secret.GetSecretList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "secrets")
 }
 
 func (apiHandler *APIHandler) handleGetSecretDetail(request *restful.Request, response *restful.Response) {
@@ -1605,13 +1928,17 @@ func (apiHandler *APIHandler) handleGetConfigMapList(request *restful.Request, r
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := configmap.GetConfigMapList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "configmaps")
 }
 
 func (apiHandler *APIHandler) handleGetConfigMapDetail(request *restful.Request, response *restful.Response) {
@@ -1639,13 +1966,17 @@ func (apiHandler *APIHandler) handleGetStatefulSetList(request *restful.Request,
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := statefulset.GetStatefulSetList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "statefulsets")
 }
 
 func (apiHandler *APIHandler) handleGetStatefulSetDetail(request *restful.Request, response *restful.Response) {
@@ -1675,13 +2006,17 @@ func (apiHandler *APIHandler) handleGetStatefulSetPods(request *restful.Request,
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("statefulset")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := statefulset.GetStatefulSetPods(k8sClient, dsQuery, name, namespace)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetStatefulSetEvents(request *restful.Request, response *restful.Response) {
@@ -1693,13 +2028,17 @@ func (apiHandler *APIHandler) handleGetStatefulSetEvents(request *restful.Reques
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("statefulset")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetResourceEvents(k8sClient, dsQuery, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetServiceList(request *restful.Request, response *restful.Response) {
@@ -1710,13 +2049,17 @@ func (apiHandler *APIHandler) handleGetServiceList(request *restful.Request, res
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := service.GetServiceList(k8sClient, namespace, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "services")
 }
 
 func (apiHandler *APIHandler) handleGetServiceDetail(request *restful.Request, response *restful.Response) {
@@ -1728,7 +2071,11 @@ func (apiHandler *APIHandler) handleGetServiceDetail(request *restful.Request, r
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("service")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := service.GetServiceDetail(k8sClient, namespace, name, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -1746,13 +2093,17 @@ func (apiHandler *APIHandler) handleGetServicePods(request *restful.Request, res
 
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("service")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := service.GetServicePods(k8sClient, namespace, name, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
 }
 
 func (apiHandler *APIHandler) handleGetIngressList(request *restful.Request, response *restful.Response) {
@@ -1762,14 +2113,18 @@ func (apiHandler *APIHandler) handleGetIngressList(request *restful.Request, res
 		return
 	}
 
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	namespace := parseNamespacePathParameter(request)
 	result, err := ingress.GetIngressList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "ingresses")
 }
 
 func (apiHandler *APIHandler) handleGetIngressDetail(request *restful.Request, response *restful.Response) {
@@ -1797,14 +2152,18 @@ func (apiHandler *APIHandler) handleGetNodeEvents(request *restful.Request, resp
 	}
 
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := event.GetNodeEvents(k8sClient, dsQuery, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "events")
 }
 
 func (apiHandler *APIHandler) handleGetNodePods(request *restful.Request, response *restful.Response) {
@@ -1815,14 +2174,46 @@ func (apiHandler *APIHandler) handleGetNodePods(request *restful.Request, respon
 	}
 
 	name := request.PathParameter("name")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := node.GetNodePods(k8sClient, dsQuery, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "pods")
+}
+
+func (apiHandler *APIHandler) handleCreateResource(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.cManager.VerberClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	createSpec := &runtime.Unknown{}
+	if err := request.ReadEntity(createSpec); err != nil {
+		kcErrors.HandleInternalError(response, k8sErrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	result, err := verber.Create(kind, ok, namespace, createSpec)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	if ok {
+		apiHandler.recordRevision(request, history.OperationCreate, kind, nil, result)
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
 func (apiHandler *APIHandler) handleDeleteResource(request *restful.Request, response *restful.Response) {
@@ -1832,15 +2223,37 @@ func (apiHandler *APIHandler) handleDeleteResource(request *restful.Request, res
 		return
 	}
 
+	options, dryRun, err := parseDeleteOptions(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
 	kind := request.PathParameter("kind")
 	namespace, ok := request.PathParameters()["namespace"]
 	name := request.PathParameter("name")
 
-	if err := verber.Delete(kind, ok, namespace, name); err != nil {
+	if dryRun {
+		preview, err := verber.DeletePreview(kind, ok, namespace, name, *options.PropagationPolicy)
+		if err != nil {
+			kcErrors.HandleInternalError(response, err)
+			return
+		}
+
+		response.WriteHeaderAndEntity(http.StatusOK, preview)
+		return
+	}
+
+	before := apiHandler.recordedObject(verber, kind, ok, namespace, name)
+	if err := verber.Delete(kind, ok, namespace, name, options); err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
+	if before != nil {
+		apiHandler.recordRevision(request, history.OperationDelete, kind, before, nil)
+	}
+
 	response.WriteHeader(http.StatusOK)
 }
 
@@ -1854,12 +2267,26 @@ func (apiHandler *APIHandler) handleGetResource(request *restful.Request, respon
 	kind := request.PathParameter("kind")
 	namespace, ok := request.PathParameters()["namespace"]
 	name := request.PathParameter("name")
+	clean, err := parseCleanParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
 	result, err := verber.Get(kind, ok, namespace, name)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
+	if clean {
+		result, err = cleanObject(result)
+		if err != nil {
+			kcErrors.HandleInternalError(response, err)
+			return
+		}
+	}
+
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
@@ -1879,14 +2306,123 @@ func (apiHandler *APIHandler) handlePutResource(request *restful.Request, respon
 		return
 	}
 
+	dryRun, err := parseDryRunParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	if dryRun {
+		result, err := verber.PutDryRun(kind, ok, namespace, name, putSpec)
+		if err != nil {
+			kcErrors.HandleInternalError(response, err)
+			return
+		}
+
+		response.WriteHeaderAndEntity(http.StatusOK, result)
+		return
+	}
+
+	before := apiHandler.recordedObject(verber, kind, ok, namespace, name)
 	if err := verber.Put(kind, ok, namespace, name, putSpec); err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
+	if before != nil {
+		apiHandler.recordRevision(request, history.OperationUpdate, kind, before,
+			apiHandler.recordedObject(verber, kind, ok, namespace, name))
+	}
+
 	response.WriteHeader(http.StatusCreated)
 }
 
+func (apiHandler *APIHandler) handlePatchResource(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.cManager.VerberClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	patchType, err := parsePatchType(request)
+	if err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusUnsupportedMediaType, err.Error()+"\n")
+		return
+	}
+
+	patch, err := ioutil.ReadAll(request.Request.Body)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	dryRun, err := parseDryRunParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	if dryRun {
+		result, err := verber.PatchDryRun(kind, ok, namespace, name, patchType, patch)
+		if err != nil {
+			kcErrors.HandleInternalError(response, err)
+			return
+		}
+
+		response.WriteHeaderAndEntity(http.StatusOK, result)
+		return
+	}
+
+	before := apiHandler.recordedObject(verber, kind, ok, namespace, name)
+	result, err := verber.Patch(kind, ok, namespace, name, patchType, patch)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	if before != nil {
+		apiHandler.recordRevision(request, history.OperationPatch, kind, before, result)
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleBulkAction(request *restful.Request, response *restful.Response) {
+	apiHandler.runBulkAction(request, response, bulk.Perform)
+}
+
+func (apiHandler *APIHandler) handleBulkActionPreview(request *restful.Request, response *restful.Response) {
+	apiHandler.runBulkAction(request, response, bulk.Preview)
+}
+
+func (apiHandler *APIHandler) runBulkAction(request *restful.Request, response *restful.Response,
+	run func(clientApi.ResourceVerber, *bulk.Spec) (*bulk.Result, error)) {
+	verber, err := apiHandler.cManager.VerberClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(bulk.Spec)
+	if err := request.ReadEntity(spec); err != nil {
+		kcErrors.HandleInternalError(response, k8sErrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	// Changes of selected objects are recorded by the verber, as they are not known to the handler.
+	result, err := run(&recordingVerber{ResourceVerber: verber, apiHandler: apiHandler, request: request}, spec)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetRbacRoleList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1894,14 +2430,18 @@ func (apiHandler *APIHandler) handleGetRbacRoleList(request *restful.Request, re
 		return
 	}
 
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := rbacroles.GetRbacRoleList(k8sClient, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "roles")
 }
 
 func (apiHandler *APIHandler) handleGetRbacRoleBindingList(request *restful.Request, response *restful.Response) {
@@ -1911,14 +2451,18 @@ func (apiHandler *APIHandler) handleGetRbacRoleBindingList(request *restful.Requ
 		return
 	}
 
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := rbacrolebindings.GetRbacRoleBindingList(k8sClient, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "rolebindings")
 }
 
 func (apiHandler *APIHandler) handleRbacStatus(request *restful.Request, response *restful.Response) {
@@ -1944,13 +2488,17 @@ func (apiHandler *APIHandler) handleGetPersistentVolumeList(request *restful.Req
 		return
 	}
 
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := persistentvolume.GetPersistentVolumeList(k8sClient, dataSelect)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "persistentvolumes")
 }
 
 func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(request *restful.Request, response *restful.Response) {
@@ -1977,13 +2525,17 @@ func (apiHandler *APIHandler) handleGetPersistentVolumeClaimList(request *restfu
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dataSelect, result, "persistentvolumeclaims")
 }
 
 func (apiHandler *APIHandler) handleGetPersistentVolumeClaimDetail(request *restful.Request, response *restful.Response) {
@@ -2010,13 +2562,17 @@ func (apiHandler *APIHandler) handleGetStorageClassList(request *restful.Request
 		return
 	}
 
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseListDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := storageclass.GetStorageClassList(k8sClient, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "storageclasses")
 }
 
 func (apiHandler *APIHandler) handleGetStorageClass(request *restful.Request, response *restful.Response) {
@@ -2043,13 +2599,17 @@ func (apiHandler *APIHandler) handleGetStorageClassPersistentVolumes(request *re
 	}
 
 	name := request.PathParameter("storageclass")
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := persistentvolume.GetStorageClassPersistentVolumes(k8sClient, name, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	writeList(response, dsQuery, result, "persistentvolumes")
 }
 
 func (apiHandler *APIHandler) handleLogSource(request *restful.Request, response *restful.Response) {
@@ -2145,7 +2705,11 @@ func (apiHandler *APIHandler) handleOverview(request *restful.Request, response
 	}
 
 	nsQuery := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
+	dsQuery, err := parseDataSelectPathParameter(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
 	result, err := overview.GetOverview(k8sClient, nsQuery, dsQuery)
 	if err != nil {
 		kcErrors.HandleInternalError(response, err)
@@ -2154,6 +2718,79 @@ func (apiHandler *APIHandler) handleOverview(request *restful.Request, response
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+// Parses 'propagationPolicy', 'gracePeriodSeconds' and 'dryRun' query parameters of raw DELETE routes.
+// Dependents are deleted in the foreground by default. Returns bad request error for invalid values.
+func parseDeleteOptions(request *restful.Request) (*metaV1.DeleteOptions, bool, error) {
+	policy := metaV1.DeletePropagationForeground
+	if param := request.QueryParameter("propagationPolicy"); len(param) > 0 {
+		policy = metaV1.DeletionPropagation(param)
+		switch policy {
+		case metaV1.DeletePropagationForeground, metaV1.DeletePropagationBackground, metaV1.DeletePropagationOrphan:
+		default:
+			return nil, false, k8sErrors.NewBadRequest(fmt.Sprintf(
+				"Invalid propagationPolicy %q, expected one of: Foreground, Background, Orphan", param))
+		}
+	}
+
+	options := &metaV1.DeleteOptions{PropagationPolicy: &policy}
+	if param := request.QueryParameter("gracePeriodSeconds"); len(param) > 0 {
+		gracePeriod, err := strconv.ParseInt(param, 10, 64)
+		if err != nil || gracePeriod < 0 {
+			return nil, false, k8sErrors.NewBadRequest(fmt.Sprintf(
+				"Invalid gracePeriodSeconds %q, expected non-negative integer", param))
+		}
+
+		options.GracePeriodSeconds = &gracePeriod
+	}
+
+	dryRun, err := parseDryRunParameter(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return options, dryRun, nil
+}
+
+// Parses 'dryRun' query parameter. Returns bad request error if it is not a boolean.
+func parseDryRunParameter(request *restful.Request) (bool, error) {
+	param := request.QueryParameter("dryRun")
+	if len(param) == 0 {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, k8sErrors.NewBadRequest(fmt.Sprintf("Invalid dryRun %q, expected boolean", param))
+	}
+
+	return dryRun, nil
+}
+
+// Content types accepted by PATCH routes of raw resources. Patch type is chosen by the content type of request.
+var patchContentTypes = []string{
+	string(types.MergePatchType),
+	string(types.StrategicMergePatchType),
+	string(types.JSONPatchType),
+}
+
+// Returns patch type matching Content-Type header of the request.
+func parsePatchType(request *restful.Request) (types.PatchType, error) {
+	contentType := request.HeaderParameter("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("Invalid Content-Type %q: %s", contentType, err.Error())
+	}
+
+	for _, patchType := range patchContentTypes {
+		if mediaType == patchType {
+			return types.PatchType(patchType), nil
+		}
+	}
+
+	return "", fmt.Errorf("Unsupported patch Content-Type %q, expected one of: %s", contentType,
+		strings.Join(patchContentTypes, ", "))
+}
+
 // Get namespaces from path parameter
 func parseNamespacePathParameter(request *restful.Request) *common.NamespaceQuery {
 	namespace := request.PathParameter("namespace")
@@ -2184,18 +2821,92 @@ func parsePaginationPathParameter(request *restful.Request) *dataselect.Paginati
 	return dataselect.NewPaginationQuery(int(itemsPerPage), int(page - 1))
 }
 
-func parseFilterPathParameter(request *restful.Request) *dataselect.FilterQuery {
-	return dataselect.NewFilterQuery(strings.Split(request.QueryParameter("filterBy"), ","))
+// Parses old 'filterBy=prop,value' filters and filter expression, i.e. '?filter=status%21%3DRunning'.
+func parseFilterPathParameter(request *restful.Request) (*dataselect.FilterQuery, error) {
+	filterQuery, err := dataselect.NewExpressionFilterQuery(strings.Split(request.QueryParameter("filterBy"), ","),
+		request.QueryParameter("filter"))
+	if err != nil {
+		return nil, k8sErrors.NewBadRequest(err.Error())
+	}
+
+	return filterQuery, nil
 }
 
 func parseSortPathParameter(request *restful.Request) *dataselect.SortQuery {
 	return dataselect.NewSortQuery(strings.Split(request.QueryParameter("sortBy"), ","))
 }
 
-// Parses query parameters of the request and returns a DataSelectQuery object
-func parseDataSelectPathParameter(request *restful.Request) *dataselect.DataSelectQuery {
+// Parses query parameters of the request and returns a DataSelectQuery object. Views that can not be exported,
+// i.e. details and views of several lists, reject the 'export' parameter instead of ignoring it.
+func parseDataSelectPathParameter(request *restful.Request) (*dataselect.DataSelectQuery, error) {
+	if len(request.QueryParameter("export")) > 0 {
+		return nil, k8sErrors.NewBadRequest("Export is supported only by lists of a single resource kind")
+	}
+
+	return parseDataSelectQuery(request)
+}
+
+func parseDataSelectQuery(request *restful.Request) (*dataselect.DataSelectQuery, error) {
 	paginationQuery := parsePaginationPathParameter(request)
 	sortQuery := parseSortPathParameter(request)
-	filterQuery := parseFilterPathParameter(request)
-	return dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery)
+	filterQuery, err := parseFilterPathParameter(request)
+	if err != nil {
+		return nil, err
+	}
+	selectorQuery := parseSelectorPathParameter(request)
+	return dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery, selectorQuery), nil
+}
+
+// Parses query parameters of lists that can be exported, i.e. '?export=csv&columns=name,restarts'. Exported
+// lists are not paginated.
+func parseExportableDataSelectPathParameter(request *restful.Request) (*dataselect.DataSelectQuery, error) {
+	dsQuery, err := parseDataSelectQuery(request)
+	if err != nil {
+		return nil, err
+	}
+
+	format := request.QueryParameter("export")
+	if len(format) == 0 {
+		return dsQuery, nil
+	}
+
+	exportQuery, err := dataselect.NewExportQuery(format, strings.Split(request.QueryParameter("columns"), ","))
+	if err != nil {
+		return nil, k8sErrors.NewBadRequest(err.Error())
+	}
+
+	dsQuery.PaginationQuery = dataselect.NoPagination
+	dsQuery.ExportQuery = exportQuery
+	return dsQuery, nil
+}
+
+// Parses query parameters of list of a single resource kind. Unlike parseDataSelectPathParameter, it accepts
+// '?chunked=true&continue=token' parameters, that request pages from apiserver, so the full list is not
+// downloaded for every page of large lists. Exported lists and lists of several namespaces, which are filtered
+// in memory, are never chunked.
+func parseListDataSelectPathParameter(request *restful.Request) (*dataselect.DataSelectQuery, error) {
+	dsQuery, err := parseExportableDataSelectPathParameter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.QueryParameter("chunked") == "true" && dsQuery.ExportQuery == nil &&
+		!parseNamespacePathParameter(request).IsMultiNamespace() {
+		dsQuery.PaginationQuery = dataselect.NewChunkedPaginationQuery(dsQuery.PaginationQuery.ItemsPerPage,
+			dsQuery.PaginationQuery.Page, request.QueryParameter("continue"))
+	}
+
+	return dsQuery, nil
+}
+
+// Parses label and field selectors passed to apiserver, i.e. '?labelSelector=app%3Dpayments'. Views that list
+// several kinds apply them only to kinds from '?selectorKinds=deployment,pod' parameter, if it is set.
+func parseSelectorPathParameter(request *restful.Request) *dataselect.SelectorQuery {
+	var kinds []string
+	if param := request.QueryParameter("selectorKinds"); len(param) > 0 {
+		kinds = strings.Split(param, ",")
+	}
+
+	return dataselect.NewKindSelectorQuery(request.QueryParameter("labelSelector"),
+		request.QueryParameter("fieldSelector"), kinds)
 }
\ No newline at end of file
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Path segment that precedes cluster name in cluster path prefix, i.e. '/api/v1/clusters/{cluster}/pod'.
+const clusterPathSegment = "clusters"
+
+// ClusterList contains names of clusters registered in addition to the default cluster.
+type ClusterList struct {
+	Clusters []string `json:"clusters"`
+}
+
+// Returns handler that selects cluster based on path prefix, so every route can be called for any registered
+// cluster, i.e. '/api/v1/clusters/prod/pod/default' is handled as '/api/v1/pod/default' with cluster header
+// set to 'prod'. Requests without the prefix are passed unchanged.
+func clusterPrefixHandler(rootPath string, next http.Handler) http.Handler {
+	prefix := rootPath + "/" + clusterPathSegment + "/"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, prefix), "/", 2)
+		if len(parts) < 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rewritten := new(http.Request)
+		*rewritten = *r
+		rewritten.URL = new(url.URL)
+		*rewritten.URL = *r.URL
+		rewritten.URL.Path = rootPath + "/" + parts[1]
+		rewritten.URL.RawPath = ""
+		rewritten.Header = make(http.Header)
+		for key, values := range r.Header {
+			rewritten.Header[key] = values
+		}
+		rewritten.Header.Set(clientApi.ClusterHeader, parts[0])
+
+		next.ServeHTTP(w, rewritten)
+	})
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClusterPrefixHandler(t *testing.T) {
+	cases := []struct {
+		path            string
+		expectedPath    string
+		expectedCluster string
+	}{
+		{"/api/v1/clusters/prod/pod/default", "/api/v1/pod/default", "prod"},
+		{"/api/v1/pod/default", "/api/v1/pod/default", ""},
+		{"/api/v1/clusters", "/api/v1/clusters", ""},
+	}
+
+	for _, c := range cases {
+		var path, cluster string
+		handler := clusterPrefixHandler("/api/v1", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			cluster = r.Header.Get(clientApi.ClusterHeader)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, c.path, nil))
+		if path != c.expectedPath || cluster != c.expectedCluster {
+			t.Errorf("clusterPrefixHandler(%s): Expected path %s and cluster '%s' but got %s and '%s'", c.path,
+				c.expectedPath, c.expectedCluster, path, cluster)
+		}
+	}
+}
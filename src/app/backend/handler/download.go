@@ -1,13 +1,24 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/emicklei/go-restful"
 	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
 	"io"
 )
 
 func handleDownload(response *restful.Response, result io.ReadCloser) {
-	response.AddHeader(restful.HEADER_ContentType, "text/plain")
+	handleFileDownload(response, result, "text/plain", "")
+}
+
+// handleFileDownload streams result to the response as content of given type. Browsers save it as a file of
+// given name, if it is not empty.
+func handleFileDownload(response *restful.Response, result io.ReadCloser, contentType, fileName string) {
+	response.AddHeader(restful.HEADER_ContentType, contentType)
+	if len(fileName) > 0 {
+		response.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	}
+
 	defer result.Close()
 	_, err := io.Copy(response, result)
 	if err != nil {
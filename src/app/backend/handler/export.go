@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/dataselect"
+	"io"
+	"net/http"
+)
+
+// writeList writes the list to the response, or, if the list is exported, streams its data cells collected by
+// the query as a file of given name.
+func writeList(response *restful.Response, dsQuery *dataselect.DataSelectQuery, list interface{},
+	name string) {
+	if dsQuery.ExportQuery == nil {
+		response.WriteHeaderAndEntity(http.StatusOK, list)
+		return
+	}
+
+	exportQuery := dsQuery.ExportQuery
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(exportQuery.Write(writer))
+	}()
+
+	handleFileDownload(response, reader, exportQuery.ContentType(), name+"."+exportQuery.Format)
+}
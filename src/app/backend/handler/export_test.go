@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/event"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/pod"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestPod(name string, restarts int32) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{RestartCount: restarts}}},
+	}
+}
+
+func TestWriteList(t *testing.T) {
+	pods := []v1.Pod{newTestPod("web", 1), newTestPod("db", 7), newTestPod("api", 3)}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/pod").To(func(request *restful.Request, response *restful.Response) {
+		dsQuery, err := parseListDataSelectPathParameter(request)
+		if err != nil {
+			response.WriteError(http.StatusBadRequest, err)
+			return
+		}
+
+		result := pod.ToPodList(pods, []v1.Event{}, nil, dsQuery)
+		writeList(response, dsQuery, result, "pods")
+	}))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	cases := []struct {
+		query        string
+		expectedCode int
+		expectedType string
+		expectedFile string
+		expected     string
+	}{
+		{"export=csv&columns=name,restarts,unknown&sortBy=d,restarts&itemsPerPage=1&page=1", http.StatusOK,
+			"text/csv", "pods.csv", "name,restarts,unknown\ndb,7,\napi,3,\nweb,1,\n"},
+		{"export=jsonl&columns=name,restarts&filter=restarts>2&chunked=true&itemsPerPage=1", http.StatusOK,
+			"application/x-ndjson", "pods.jsonl", "{\"name\":\"db\",\"restarts\":7}\n{\"name\":\"api\",\"restarts\":3}\n"},
+		{"export=yaml&columns=name,restarts&filterBy=name,web", http.StatusOK, "application/yaml",
+			"pods.yaml", "- name: web\n  restarts: 1\n"},
+		{"export=xml", http.StatusBadRequest, "", "", ""},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/pod?"+c.query, nil))
+
+		if recorder.Code != c.expectedCode {
+			t.Errorf("writeList(%s): Expected status %d but got %d", c.query, c.expectedCode, recorder.Code)
+			continue
+		}
+
+		if c.expectedCode == http.StatusOK && (recorder.Header().Get("Content-Type") != c.expectedType ||
+			recorder.Body.String() != c.expected) {
+			t.Errorf("writeList(%s): Expected %s %q but got %s %q", c.query, c.expectedType, c.expected,
+				recorder.Header().Get("Content-Type"), recorder.Body.String())
+		}
+
+		disposition := recorder.Header().Get("Content-Disposition")
+		if c.expectedCode == http.StatusOK && disposition != `attachment; filename="`+c.expectedFile+`"` {
+			t.Errorf("writeList(%s): Expected download of %s but got %q", c.query, c.expectedFile, disposition)
+		}
+	}
+}
+
+func TestWriteList_Events(t *testing.T) {
+	events := []v1.Event{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "b", Namespace: "default"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "a", Namespace: "default"}},
+	}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/event").To(func(request *restful.Request, response *restful.Response) {
+		dsQuery, err := parseExportableDataSelectPathParameter(request)
+		if err != nil {
+			response.WriteError(http.StatusBadRequest, err)
+			return
+		}
+
+		result := event.CreateEventList(events, dsQuery)
+		writeList(response, dsQuery, result, "events")
+	}))
+	ws.Route(ws.GET("/workload").To(func(request *restful.Request, response *restful.Response) {
+		if _, err := parseDataSelectPathParameter(request); err != nil {
+			response.WriteError(http.StatusBadRequest, err)
+		}
+	}))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+		"/api/v1/event?export=csv&columns=name&sortBy=a,name&itemsPerPage=1&page=1", nil))
+	if expected := "name\na\nb\n"; recorder.Code != http.StatusOK || recorder.Body.String() != expected {
+		t.Errorf("writeList(): Expected events %q but got %d %q", expected, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/workload?export=csv", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("parseDataSelectPathParameter(): Expected export to be rejected but got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
+	"golang.org/x/net/xsrftoken"
+	"net/http"
+	"strings"
+)
+
+// Rejects requests to resources that are used internally by k8sconsole, i.e. secret that holds
+// JWE encryption key.
+func restrictedResourcesFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	if !authApi.ShouldRejectRquest(request.Request.URL.String()) {
+		chain.ProcessFilter(request, response)
+		return
+	}
+
+	response.AddHeader("Content-Type", "text/plain")
+	response.WriteErrorString(http.StatusForbidden, kcErrors.MSG_K8SCONSOLE_EXCLUSIVE_RESOURCE_ERROR+"\n")
+}
+
+// Reports status of resource cache in the response header of read requests if the cache is enabled, so clients
+// know that lists served from memory may be outdated.
+func resourceCacheFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	if cache := common.GetResourceCache(); cache != nil && request.Request.Method == http.MethodGet {
+		response.AddHeader(api.CacheStatusHeader, cache.Status())
+	}
+
+	chain.ProcessFilter(request, response)
+}
+
+// Returns filter that rejects requests that can modify resources if they do not contain valid csrf token.
+// Token has to be generated for the action that equals the first segment of the route path,
+// i.e. 'deploy' for '/api/v1/deploy' or '_raw' for '/api/v1/_raw/{kind}/name/{name}'.
+func csrfFilter(rootPath string, csrfKey func() string) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if !shouldValidateCsrfToken(request) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		action := csrfAction(rootPath, request.Request.URL.Path)
+		token := request.HeaderParameter(api.CsrfTokenHeader)
+		if len(token) == 0 {
+			writeCsrfError(response, fmt.Sprintf("Missing %s header. Token for action '%s' is required.",
+				api.CsrfTokenHeader, action))
+			return
+		}
+
+		if !xsrftoken.Valid(token, csrfKey(), "none", action) {
+			writeCsrfError(response, fmt.Sprintf("Invalid or expired %s header for action '%s'.",
+				api.CsrfTokenHeader, action))
+			return
+		}
+
+		chain.ProcessFilter(request, response)
+	}
+}
+
+func shouldValidateCsrfToken(request *restful.Request) bool {
+	switch request.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func csrfAction(rootPath, path string) string {
+	path = strings.Trim(strings.TrimPrefix(path, rootPath), "/")
+	return strings.Split(path, "/")[0]
+}
+
+func writeCsrfError(response *restful.Response, message string) {
+	response.AddHeader("Content-Type", "text/plain")
+	response.WriteErrorString(http.StatusForbidden, kcErrors.MSG_CSRF_VALIDATION_ERROR+": "+message+"\n")
+}
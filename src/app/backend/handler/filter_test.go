@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	"golang.org/x/net/xsrftoken"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCsrfFilter(t *testing.T) {
+	csrfKey := "test-key"
+	ws := new(restful.WebService)
+	ws.Path("/api/v1").Filter(csrfFilter("/api/v1", func() string { return csrfKey }))
+	handle := func(request *restful.Request, response *restful.Response) {
+		response.WriteHeader(http.StatusOK)
+	}
+	ws.Route(ws.GET("/deploy").To(handle))
+	ws.Route(ws.POST("/deploy").To(handle))
+	ws.Route(ws.DELETE("/_raw/{kind}/name/{name}").To(handle))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	cases := []struct {
+		info     string
+		method   string
+		path     string
+		token    string
+		expected int
+	}{
+		{"Should not validate safe methods", http.MethodGet, "/api/v1/deploy", "", http.StatusOK},
+		{"Should reject request without token", http.MethodPost, "/api/v1/deploy", "", http.StatusForbidden},
+		{"Should reject token generated for other action", http.MethodPost, "/api/v1/deploy",
+			xsrftoken.Generate(csrfKey, "none", "_raw"), http.StatusForbidden},
+		{"Should accept valid token", http.MethodPost, "/api/v1/deploy",
+			xsrftoken.Generate(csrfKey, "none", "deploy"), http.StatusOK},
+		{"Should use first path segment as action", http.MethodDelete, "/api/v1/_raw/pod/name/test",
+			xsrftoken.Generate(csrfKey, "none", "_raw"), http.StatusOK},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, c.path, nil)
+		if len(c.token) > 0 {
+			request.Header.Set(api.CsrfTokenHeader, c.token)
+		}
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, request)
+
+		if recorder.Code != c.expected {
+			t.Errorf("Test case: %s. Expected status %d, but got %d.", c.info, c.expected, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/wzt3309/k8sconsole/src/app/backend/access"
+	clientApi "github.com/wzt3309/k8sconsole/src/app/backend/client/api"
+	kcErrors "github.com/wzt3309/k8sconsole/src/app/backend/errors"
+	"github.com/wzt3309/k8sconsole/src/app/backend/history"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Metadata of object returned by the verber.
+type partialObject struct {
+	Metadata metaV1.ObjectMeta `json:"metadata"`
+}
+
+func objectMeta(object runtime.Object) (metaV1.ObjectMeta, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return metaV1.ObjectMeta{}, err
+	}
+
+	partial := partialObject{}
+	if err := json.Unmarshal(raw, &partial); err != nil {
+		return metaV1.ObjectMeta{}, err
+	}
+
+	return partial.Metadata, nil
+}
+
+// Returns the verber, the recorder and the resource of the object whose history is read by the request. Bad
+// request error is returned if the history is disabled. History is read only if the user can get the object.
+// Deleted objects can not be read anymore, so the access is reviewed instead.
+func (apiHandler *APIHandler) historyClients(request *restful.Request) (clientApi.ResourceVerber,
+	*history.Recorder, *clientApi.Resource, error) {
+	recorder := history.GetRecorder()
+	if recorder == nil {
+		return nil, nil, nil, k8sErrors.NewBadRequest("Revision history is disabled")
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	resource, err := verber.Resolve(request.PathParameter("kind"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !resource.Namespaced {
+		return nil, nil, nil, k8sErrors.NewBadRequest(fmt.Sprintf("History of cluster scoped kind %s is not "+
+			"recorded", resource.Kind))
+	}
+
+	client, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	check := access.AccessCheck{
+		Namespace: request.PathParameter("namespace"),
+		Verb:      "get",
+		Group:     resource.Group,
+		Resource:  resource.Resource,
+		Name:      request.PathParameter("name"),
+	}
+	result, err := access.CanI(client, &access.AccessCheckSpec{Checks: []access.AccessCheck{check}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !result.Results[0].Allowed {
+		return nil, nil, nil, k8sErrors.NewForbidden(schema.GroupResource{Group: resource.Group,
+			Resource: resource.Resource}, check.Name, errors.New("History is readable only by users that can "+
+			"get the object"))
+	}
+
+	return verber, recorder, resource, nil
+}
+
+// Records change of namespaced object made by the request. Before is nil for created objects and after is nil
+// for deleted objects. The change has been already made, so failures are only logged.
+func (apiHandler *APIHandler) recordRevision(request *restful.Request, operation, kind string, before,
+	after runtime.Object) {
+	if history.GetRecorder() == nil || (before == nil && after == nil) {
+		return
+	}
+
+	apiHandler.recordUserRevision(request, apiHandler.username(request), operation, kind, before, after)
+}
+
+// Records change made by the user with given name. It is used by requests that change many objects, so the user
+// is resolved only once.
+func (apiHandler *APIHandler) recordUserRevision(request *restful.Request, user, operation, kind string, before,
+	after runtime.Object) {
+	recorder := history.GetRecorder()
+	if recorder == nil || (before == nil && after == nil) {
+		return
+	}
+
+	if err := apiHandler.doRecordRevision(recorder, request, user, operation, kind, before, after); err != nil {
+		log.Printf("Could not record %s of %s: %s", operation, kind, err)
+	}
+}
+
+func (apiHandler *APIHandler) doRecordRevision(recorder *history.Recorder, request *restful.Request, user,
+	operation, kind string, before, after runtime.Object) error {
+	// History is stored with k8sconsole service account, so users can not change the recorded revisions.
+	client, err := apiHandler.cManager.InsecureClusterClient(request)
+	if err != nil {
+		return err
+	}
+
+	// Revisions are kept by kind registered in apiserver, as kinds of requests can be given in many forms.
+	verber, err := apiHandler.cManager.VerberClient(request)
+	if err != nil {
+		return err
+	}
+
+	resource, err := verber.Resolve(kind)
+	if err != nil {
+		return err
+	}
+
+	if !resource.Namespaced {
+		return nil
+	}
+
+	var beforeRaw []byte
+	if before != nil {
+		if beforeRaw, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+
+	var afterRaw []byte
+	if after != nil {
+		if afterRaw, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	revision, err := history.NewRevision(operation, resource.Kind, user, beforeRaw, afterRaw)
+	if err != nil {
+		return err
+	}
+
+	return recorder.Record(client, request.HeaderParameter(clientApi.ClusterHeader), revision)
+}
+
+// Returns name of the user that sends the request or empty string if it can not be determined.
+func (apiHandler *APIHandler) username(request *restful.Request) string {
+	authInfo, err := apiHandler.authInfo(request)
+	if err != nil {
+		return ""
+	}
+
+	// Token is reviewed by the cluster it has been issued for.
+	client, err := apiHandler.cManager.InsecureClusterClient(request)
+	if err != nil {
+		return ""
+	}
+
+	identity, err := access.GetIdentity(client, authInfo)
+	if err != nil {
+		return ""
+	}
+
+	return identity.Username
+}
+
+func (apiHandler *APIHandler) handleGetHistory(request *restful.Request, response *restful.Response) {
+	_, recorder, resource, err := apiHandler.historyClients(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	client, err := apiHandler.cManager.InsecureClusterClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := recorder.List(client, request.HeaderParameter(clientApi.ClusterHeader),
+		request.PathParameter("namespace"), resource.Kind, request.PathParameter("name"))
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleRevert(request *restful.Request, response *restful.Response) {
+	verber, recorder, resource, err := apiHandler.historyClients(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	client, err := apiHandler.cManager.InsecureClusterClient(request)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	param := request.PathParameter("revision")
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		kcErrors.HandleInternalError(response,
+			k8sErrors.NewBadRequest(fmt.Sprintf("Invalid revision %q, expected integer", param)))
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	revision, err := recorder.Get(client, request.HeaderParameter(clientApi.ClusterHeader), namespace,
+		resource.Kind, name, id)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	live, err := verber.Get(kind, true, namespace, name)
+	if k8sErrors.IsNotFound(err) {
+		apiHandler.revertDelete(request, response, verber, revision)
+		return
+	}
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	meta, err := objectMeta(live)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	// Object is put with resource version of the live object, so it replaces changes made after the revision.
+	object, err := revision.RevertObject(meta.ResourceVersion)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := verber.Put(kind, true, namespace, name, &runtime.Unknown{Raw: object}); err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	reverted, err := verber.Get(kind, true, namespace, name)
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	apiHandler.recordRevision(request, history.OperationRevert, kind, live, reverted)
+	response.WriteHeaderAndEntity(http.StatusOK, reverted)
+}
+
+// Creates object that has been deleted after the revision again, as it was before the revision.
+func (apiHandler *APIHandler) revertDelete(request *restful.Request, response *restful.Response,
+	verber clientApi.ResourceVerber, revision *history.Revision) {
+	object, err := revision.RevertObject("")
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	created, err := verber.Create(kind, true, request.PathParameter("namespace"), &runtime.Unknown{Raw: object})
+	if err != nil {
+		kcErrors.HandleInternalError(response, err)
+		return
+	}
+
+	apiHandler.recordRevision(request, history.OperationRevert, kind, nil, created)
+	response.WriteHeaderAndEntity(http.StatusCreated, created)
+}
+
+// Verber that records deletes and patches of namespaced objects, made i.e. by bulk actions. It is used
+// concurrently, so the user is resolved only by the first recorded change.
+type recordingVerber struct {
+	clientApi.ResourceVerber
+	apiHandler *APIHandler
+	request    *restful.Request
+	user       string
+	userOnce   sync.Once
+}
+
+func (self *recordingVerber) username() string {
+	self.userOnce.Do(func() {
+		self.user = self.apiHandler.username(self.request)
+	})
+
+	return self.user
+}
+
+func (self *recordingVerber) Delete(kind string, namespaceSet bool, namespace string, name string,
+	options *metaV1.DeleteOptions) error {
+	before := self.apiHandler.recordedObject(self.ResourceVerber, kind, namespaceSet, namespace, name)
+	if err := self.ResourceVerber.Delete(kind, namespaceSet, namespace, name, options); err != nil {
+		return err
+	}
+
+	if before != nil {
+		self.apiHandler.recordUserRevision(self.request, self.username(), history.OperationDelete, kind, before,
+			nil)
+	}
+	return nil
+}
+
+func (self *recordingVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, patch []byte) (runtime.Object, error) {
+	before := self.apiHandler.recordedObject(self.ResourceVerber, kind, namespaceSet, namespace, name)
+	result, err := self.ResourceVerber.Patch(kind, namespaceSet, namespace, name, patchType, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if before != nil {
+		self.apiHandler.recordUserRevision(self.request, self.username(), history.OperationPatch, kind, before,
+			result)
+	}
+	return result, nil
+}
+
+// Returns the live object whose change is recorded, or nil if the history is disabled, the object is cluster
+// scoped or can not be read.
+func (apiHandler *APIHandler) recordedObject(verber clientApi.ResourceVerber, kind string, namespaceSet bool,
+	namespace, name string) runtime.Object {
+	if history.GetRecorder() == nil || !namespaceSet {
+		return nil
+	}
+
+	object, err := verber.Get(kind, namespaceSet, namespace, name)
+	if err != nil {
+		return nil
+	}
+
+	return object
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/ghodss/yaml"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	"io/ioutil"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"strconv"
+)
+
+// MimeYAML is the media type of YAML request and response entities.
+const MimeYAML = "application/yaml"
+
+// Media types of YAML entities. Besides MimeYAML, types used by older clients are accepted as well.
+var yamlContentTypes = []string{MimeYAML, "application/x-yaml", "text/yaml"}
+
+// Content types accepted and produced by GET and PUT routes of raw resources.
+var rawContentTypes = append([]string{restful.MIME_JSON}, yamlContentTypes...)
+
+func init() {
+	for _, contentType := range yamlContentTypes {
+		restful.RegisterEntityAccessor(contentType, yamlEntityAccessor{contentType: contentType})
+	}
+}
+
+// Reads and writes YAML entities by converting them from and to JSON, so JSON tags and custom JSON
+// (un)marshalers, i.e. the one of runtime.Unknown, are respected.
+type yamlEntityAccessor struct {
+	contentType string
+}
+
+// Read implements restful.EntityReaderWriter.
+func (self yamlEntityAccessor) Read(request *restful.Request, v interface{}) error {
+	data, err := ioutil.ReadAll(request.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return k8sErrors.NewBadRequest(fmt.Sprintf("Invalid YAML: %s", err))
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Write implements restful.EntityReaderWriter.
+func (self yamlEntityAccessor) Write(response *restful.Response, status int, v interface{}) error {
+	if v == nil {
+		response.WriteHeader(status)
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	data, err = yaml.JSONToYAML(data)
+	if err != nil {
+		return err
+	}
+
+	response.Header().Set(restful.HEADER_ContentType, self.contentType)
+	response.WriteHeader(status)
+	_, err = response.Write(data)
+	return err
+}
+
+// Returns value of the 'clean' query parameter that requests removal of fields populated by apiserver.
+func parseCleanParameter(request *restful.Request) (bool, error) {
+	param := request.QueryParameter("clean")
+	if len(param) == 0 {
+		return false, nil
+	}
+
+	clean, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, k8sErrors.NewBadRequest(fmt.Sprintf("Invalid clean %q, expected boolean", param))
+	}
+
+	return clean, nil
+}
+
+// Removes status and metadata fields populated by apiserver, so the object can be created again, i.e.
+// in other cluster.
+func cleanObject(object runtime.Object) (runtime.Object, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	api.RemoveServerPopulatedFields(fields)
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.Unknown{Raw: data, ContentType: runtime.ContentTypeJSON}, nil
+}
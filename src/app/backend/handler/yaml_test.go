@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestYamlEntityAccessor(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/api/v1").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
+	ws.Route(ws.PUT("/_raw/{kind}/name/{name}").
+		Consumes(rawContentTypes...).
+		Produces(rawContentTypes...).
+		To(func(request *restful.Request, response *restful.Response) {
+			object := &runtime.Unknown{}
+			if err := request.ReadEntity(object); err != nil {
+				response.WriteError(http.StatusBadRequest, err)
+				return
+			}
+			response.WriteHeaderAndEntity(http.StatusOK, object)
+		}))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	cases := []struct {
+		info         string
+		contentType  string
+		accept       string
+		body         string
+		expectedType string
+		expected     string
+	}{
+		{"Should read and write YAML", MimeYAML, MimeYAML, "kind: Pod\nmetadata:\n  name: test\n",
+			MimeYAML, "kind: Pod\nmetadata:\n  name: test\n"},
+		{"Should write JSON read from YAML", "application/x-yaml", restful.MIME_JSON, "kind: Pod\n",
+			restful.MIME_JSON, "{\n \"kind\": \"Pod\"\n}"},
+		{"Should write YAML read from JSON", restful.MIME_JSON, "text/yaml", `{"kind":"Pod"}`,
+			"text/yaml", "kind: Pod\n"},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/_raw/pod/name/test", strings.NewReader(c.body))
+		request.Header.Set("Content-Type", c.contentType)
+		request.Header.Set("Accept", c.accept)
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != c.expectedType ||
+			strings.TrimSpace(recorder.Body.String()) != strings.TrimSpace(c.expected) {
+			t.Errorf("Test case: %s. Expected %s response %q, but got %d %s %q.", c.info, c.expectedType,
+				c.expected, recorder.Code, recorder.Header().Get("Content-Type"), recorder.Body.String())
+		}
+	}
+}
+
+func TestCleanObject(t *testing.T) {
+	object := &runtime.Unknown{Raw: []byte(`{"kind":"Pod","metadata":{"name":"test","uid":"1",
+		"resourceVersion":"2","creationTimestamp":"2018-01-01T00:00:00Z","managedFields":[]},
+		"spec":{"nodeName":"node"},"status":{"phase":"Running"}}`)}
+
+	actual, err := cleanObject(object)
+	if err != nil {
+		t.Fatalf("cleanObject(): Unexpected error: %s", err)
+	}
+
+	expected := `{"kind":"Pod","metadata":{"name":"test"},"spec":{"nodeName":"node"}}`
+	if raw := string(actual.(*runtime.Unknown).Raw); raw != expected {
+		t.Errorf("cleanObject(): Expected %s, but got %s", expected, raw)
+	}
+}
@@ -0,0 +1,289 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	authApi "github.com/wzt3309/k8sconsole/src/app/backend/auth/api"
+	"k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sync"
+)
+
+// Names of backends the history can be stored in.
+const (
+	BackendMemory    = "memory"
+	BackendConfigMap = "configmap"
+	BackendSecret    = "secret"
+)
+
+// Name of ConfigMap or Secret that holds the history of all objects of its namespace. It can not be accessed
+// through k8sconsole API, so revisions can not be changed before they are reverted.
+const historyObjectName = authApi.HistoryHolderName
+
+// Key of ConfigMap or Secret the revisions are stored under.
+const revisionsKey = "revisions"
+
+// Maximal size (in bytes) of revisions stored for a single namespace. It is kept well below the size limit
+// of objects stored in etcd, as Secret data is base64 encoded in requests. The oldest revisions are removed
+// first when the history does not fit.
+const maxStoredBytes = 512 * 1024
+
+// Maximal number of namespaces memory backend keeps the history of. History of the namespace changed least
+// recently is removed first.
+const maxMemoryNamespaces = 100
+
+// Number of attempts to save the history when it is concurrently modified by other k8sconsole replica.
+const saveAttempts = 3
+
+// Backend stores revisions of all objects of a namespace. Client of the cluster and its name are passed to
+// backends, as namespaces of different clusters have separate histories. The client should use k8sconsole
+// service account, so users can not change recorded revisions.
+type Backend interface {
+	// Load returns revisions recorded in the namespace, the oldest first. Empty list is returned for unknown
+	// namespaces.
+	Load(client kubernetes.Interface, cluster, namespace string) ([]Revision, error)
+	// Update replaces revisions recorded in the namespace with the result of given function, which gets the
+	// stored revisions. The function is called again if the revisions have been concurrently modified.
+	// Backends keep only the newest revisions that fit in maxStoredBytes.
+	Update(client kubernetes.Interface, cluster, namespace string, update func([]Revision) []Revision) error
+	// Confidential returns true if the backend keeps data of recorded objects readable only for users that can
+	// read Secrets. Data of Secrets is redacted when it is recorded in other backends.
+	Confidential() bool
+}
+
+// NewBackend creates backend of given name.
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case BackendMemory:
+		return NewMemoryBackend(), nil
+	case BackendConfigMap:
+		return configMapBackend{}, nil
+	case BackendSecret:
+		return secretBackend{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown history backend %q, expected one of %s, %s or %s", name, BackendMemory,
+			BackendConfigMap, BackendSecret)
+	}
+}
+
+// Keeps the history in memory of k8sconsole. It is lost on restart and not shared between replicas.
+type memoryBackend struct {
+	// Marshalled revisions mapped by cluster and namespace.
+	revisions map[string][]byte
+	// Clusters and namespaces ordered from the least recently changed one.
+	order []string
+	mux   sync.Mutex
+}
+
+// NewMemoryBackend creates backend that keeps the history in memory.
+func NewMemoryBackend() Backend {
+	return &memoryBackend{revisions: make(map[string][]byte)}
+}
+
+// Confidential implements Backend. The history is kept in memory of k8sconsole only.
+func (self *memoryBackend) Confidential() bool {
+	return true
+}
+
+// Load implements Backend.
+func (self *memoryBackend) Load(client kubernetes.Interface, cluster, namespace string) ([]Revision, error) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	return unmarshalRevisions(self.revisions[memoryKey(cluster, namespace)])
+}
+
+// Update implements Backend.
+func (self *memoryBackend) Update(client kubernetes.Interface, cluster, namespace string,
+	update func([]Revision) []Revision) error {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	key := memoryKey(cluster, namespace)
+	revisions, err := unmarshalRevisions(self.revisions[key])
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalRevisions(update(revisions))
+	if err != nil {
+		return err
+	}
+
+	for i, each := range self.order {
+		if each == key {
+			self.order = append(self.order[:i], self.order[i+1:]...)
+			break
+		}
+	}
+
+	self.order = append(self.order, key)
+	self.revisions[key] = data
+	for len(self.order) > maxMemoryNamespaces {
+		delete(self.revisions, self.order[0])
+		self.order = self.order[1:]
+	}
+
+	return nil
+}
+
+func memoryKey(cluster, namespace string) string {
+	return cluster + "/" + namespace
+}
+
+// Keeps the history of every namespace in a ConfigMap in the namespace.
+type configMapBackend struct{}
+
+// Confidential implements Backend. ConfigMaps are readable by users that can not read Secrets.
+func (configMapBackend) Confidential() bool {
+	return false
+}
+
+// Load implements Backend.
+func (configMapBackend) Load(client kubernetes.Interface, cluster, namespace string) ([]Revision, error) {
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(historyObjectName, metaV1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return []Revision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalRevisions([]byte(configMap.Data[revisionsKey]))
+}
+
+// Update implements Backend.
+func (configMapBackend) Update(client kubernetes.Interface, cluster, namespace string,
+	update func([]Revision) []Revision) error {
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	for attempt := 1; ; attempt++ {
+		configMap, err := configMaps.Get(historyObjectName, metaV1.GetOptions{})
+		exists := err == nil
+		if k8sErrors.IsNotFound(err) {
+			configMap, err = &v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: historyObjectName}}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := updateRevisions([]byte(configMap.Data[revisionsKey]), update)
+		if err != nil {
+			return err
+		}
+
+		configMap.Data = map[string]string{revisionsKey: string(data)}
+		if exists {
+			_, err = configMaps.Update(configMap)
+		} else {
+			_, err = configMaps.Create(configMap)
+		}
+
+		if attempt < saveAttempts && (k8sErrors.IsConflict(err) || k8sErrors.IsAlreadyExists(err)) {
+			continue
+		}
+
+		return err
+	}
+}
+
+// Keeps the history of every namespace in a Secret in the namespace. It should be used when changes
+// of Secrets are recorded, as their data is redacted in ConfigMaps.
+type secretBackend struct{}
+
+// Confidential implements Backend.
+func (secretBackend) Confidential() bool {
+	return true
+}
+
+// Load implements Backend.
+func (secretBackend) Load(client kubernetes.Interface, cluster, namespace string) ([]Revision, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(historyObjectName, metaV1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return []Revision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalRevisions(secret.Data[revisionsKey])
+}
+
+// Update implements Backend.
+func (secretBackend) Update(client kubernetes.Interface, cluster, namespace string,
+	update func([]Revision) []Revision) error {
+	secrets := client.CoreV1().Secrets(namespace)
+	for attempt := 1; ; attempt++ {
+		secret, err := secrets.Get(historyObjectName, metaV1.GetOptions{})
+		exists := err == nil
+		if k8sErrors.IsNotFound(err) {
+			secret, err = &v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: historyObjectName}}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := updateRevisions(secret.Data[revisionsKey], update)
+		if err != nil {
+			return err
+		}
+
+		secret.Data = map[string][]byte{revisionsKey: data}
+		if exists {
+			_, err = secrets.Update(secret)
+		} else {
+			_, err = secrets.Create(secret)
+		}
+
+		if attempt < saveAttempts && (k8sErrors.IsConflict(err) || k8sErrors.IsAlreadyExists(err)) {
+			continue
+		}
+
+		return err
+	}
+}
+
+// Applies the update to marshalled revisions and returns the marshalled result.
+func updateRevisions(data []byte, update func([]Revision) []Revision) ([]byte, error) {
+	revisions, err := unmarshalRevisions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalRevisions(update(revisions))
+}
+
+// Marshals the newest revisions that fit in maxStoredBytes. Error is returned if even the newest revision does
+// not fit.
+func marshalRevisions(revisions []Revision) ([]byte, error) {
+	for {
+		data, err := json.Marshal(revisions)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) <= maxStoredBytes {
+			return data, nil
+		}
+
+		if len(revisions) == 1 {
+			return nil, fmt.Errorf("Revision is larger than %d bytes and can not be stored", maxStoredBytes)
+		}
+
+		revisions = revisions[1:]
+	}
+}
+
+func unmarshalRevisions(data []byte) ([]Revision, error) {
+	revisions := make([]Revision, 0)
+	if len(data) == 0 {
+		return revisions, nil
+	}
+
+	if err := json.Unmarshal(data, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
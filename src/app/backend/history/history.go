@@ -0,0 +1,359 @@
+// Package history records objects before and after they are changed through k8sconsole, so the changes can
+// be reviewed and reverted also for kinds that do not keep revisions on their own.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"strings"
+	"sync"
+)
+
+// Operations that are recorded.
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationPatch  = "patch"
+	OperationScale  = "scale"
+	OperationDelete = "delete"
+	OperationRevert = "revert"
+)
+
+// Value Secret data is replaced with when it is recorded in backend that is not confidential.
+const redactedValue = "<redacted>"
+
+// Revision describes a single change of an object made through k8sconsole. Revisions are kept by kind,
+// namespace and name of the object, so they outlive deletes of the object.
+type Revision struct {
+	// ID of the revision, unique among revisions of the namespace and increasing with every change.
+	ID        int64  `json:"id"`
+	Operation string `json:"operation"`
+	// Kind of the object as registered in apiserver, i.e. 'Deployment'.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// UID of the changed object. Objects created again with the same name have different UIDs.
+	UID types.UID `json:"uid"`
+	// User is the name of the user that made the change. Empty if it can not be determined.
+	User      string      `json:"user"`
+	Timestamp metaV1.Time `json:"timestamp"`
+	// Before is the object before the change. It is empty for created objects.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the object after the change. It is empty for deleted objects.
+	After json.RawMessage `json:"after,omitempty"`
+	// Redacted is true if data of recorded Secret has been removed. Such revisions can not be reverted.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// RevisionList contains revisions of a single object, the newest first.
+type RevisionList struct {
+	ListMeta  api.ListMeta `json:"listMeta"`
+	Revisions []Revision   `json:"revisions"`
+}
+
+// Metadata of recorded object.
+type partialObject struct {
+	Metadata metaV1.ObjectMeta `json:"metadata"`
+}
+
+// NewRevision creates revision of object of given kind changed by given user. Status and metadata populated by
+// apiserver are not recorded, as they are not restored on revert. The object is identified by metadata of after
+// object, or before object for deleted objects.
+func NewRevision(operation, kind, user string, before, after []byte) (*Revision, error) {
+	identifying := after
+	if len(identifying) == 0 {
+		identifying = before
+	}
+
+	object := partialObject{}
+	if err := json.Unmarshal(identifying, &object); err != nil {
+		return nil, err
+	}
+
+	revision := &Revision{
+		Operation: operation,
+		Kind:      kind,
+		Namespace: object.Metadata.Namespace,
+		Name:      object.Metadata.Name,
+		UID:       object.Metadata.UID,
+		User:      user,
+	}
+
+	var err error
+	if len(before) > 0 {
+		if revision.Before, err = withoutServerPopulatedFields(before); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(after) > 0 {
+		if revision.After, err = withoutServerPopulatedFields(after); err != nil {
+			return nil, err
+		}
+	}
+
+	return revision, nil
+}
+
+// RevertObject returns object the revision has changed, to be put in place of the live object with given
+// resource version. Empty resource version means that the object has been deleted and is created again.
+// Revisions of created objects can not be reverted.
+func (self *Revision) RevertObject(resourceVersion string) ([]byte, error) {
+	if len(self.Before) == 0 {
+		return nil, k8sErrors.NewBadRequest(fmt.Sprintf("Revision %d has created the object and can not be "+
+			"reverted", self.ID))
+	}
+
+	if self.Redacted {
+		return nil, k8sErrors.NewBadRequest(fmt.Sprintf("Revision %d does not hold Secret data and can not be "+
+			"reverted", self.ID))
+	}
+
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(self.Before, &object); err != nil {
+		return nil, err
+	}
+
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok && len(resourceVersion) > 0 {
+		metadata["resourceVersion"] = resourceVersion
+	}
+
+	return json.Marshal(object)
+}
+
+func withoutServerPopulatedFields(raw []byte) (json.RawMessage, error) {
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	api.RemoveServerPopulatedFields(object)
+	return json.Marshal(object)
+}
+
+// Returns true if the revision records change of a Secret. Kind of raw routes is checked together with kind
+// of the recorded object, as objects returned by typed clients may not have it set.
+func (self *Revision) isSecret() bool {
+	if strings.EqualFold(self.Kind, "secret") || strings.EqualFold(self.Kind, "secrets") {
+		return true
+	}
+
+	for _, raw := range []json.RawMessage{self.Before, self.After} {
+		object := struct {
+			Kind string `json:"kind"`
+		}{}
+		if len(raw) > 0 && json.Unmarshal(raw, &object) == nil && object.Kind == "Secret" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Replaces values of Secret data in both recorded objects. Keys are kept, so the revision still shows which
+// keys have been changed.
+func (self *Revision) redact() error {
+	var err error
+	if self.Before, err = redactSecretData(self.Before); err != nil {
+		return err
+	}
+
+	if self.After, err = redactSecretData(self.After); err != nil {
+		return err
+	}
+
+	self.Redacted = true
+	return nil
+}
+
+func redactSecretData(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	for _, field := range []string{"data", "stringData"} {
+		if data, ok := object[field].(map[string]interface{}); ok {
+			for key := range data {
+				data[key] = redactedValue
+			}
+		}
+	}
+
+	return json.Marshal(object)
+}
+
+// Recorder keeps bounded number of revisions of every object in the backend.
+type Recorder struct {
+	backend Backend
+	// Maximal number of revisions kept for a single object. The oldest revisions are removed first.
+	size int
+	// Revisions waiting to be stored mapped by cluster and namespace.
+	pending map[string]*batch
+	// Clusters and namespaces whose revisions are being stored. Only one update of the history of a namespace
+	// runs at a time, so concurrent changes do not overwrite each other.
+	storing map[string]bool
+	mux     sync.Mutex
+}
+
+// Revisions of a namespace stored by a single update of the backend.
+type batch struct {
+	revisions []*Revision
+	// Closed when the revisions have been stored.
+	done chan struct{}
+	err  error
+}
+
+// NewRecorder creates recorder that keeps up to size revisions of every object in given backend.
+func NewRecorder(backend Backend, size int) *Recorder {
+	return &Recorder{
+		backend: backend,
+		size:    size,
+		pending: make(map[string]*batch),
+		storing: make(map[string]bool),
+	}
+}
+
+// Record assigns ID and timestamp to the revision and stores it using given client of the cluster with given
+// name the object belongs to. Secret data is redacted if the backend is not confidential. Revisions of
+// a namespace recorded while its history is being stored are stored together by the next update, so changes
+// of many objects do not wait for each other.
+func (self *Recorder) Record(client kubernetes.Interface, cluster string, revision *Revision) error {
+	if !self.backend.Confidential() && revision.isSecret() {
+		if err := revision.redact(); err != nil {
+			return err
+		}
+	}
+
+	key := cluster + "/" + revision.Namespace
+	self.mux.Lock()
+	pending, ok := self.pending[key]
+	if !ok {
+		pending = &batch{done: make(chan struct{})}
+		self.pending[key] = pending
+	}
+	pending.revisions = append(pending.revisions, revision)
+
+	if self.storing[key] {
+		self.mux.Unlock()
+		<-pending.done
+		return pending.err
+	}
+
+	// No update of the namespace runs, so this call stores pending revisions until there are none left.
+	self.storing[key] = true
+	self.mux.Unlock()
+
+	for {
+		self.mux.Lock()
+		next, ok := self.pending[key]
+		if !ok {
+			delete(self.storing, key)
+			self.mux.Unlock()
+			return pending.err
+		}
+		delete(self.pending, key)
+		self.mux.Unlock()
+
+		next.err = self.store(client, cluster, revision.Namespace, next.revisions)
+		close(next.done)
+	}
+}
+
+// Stores revisions of the namespace with a single update of the backend.
+func (self *Recorder) store(client kubernetes.Interface, cluster, namespace string, recorded []*Revision) error {
+	return self.backend.Update(client, cluster, namespace, func(revisions []Revision) []Revision {
+		for _, revision := range recorded {
+			revision.ID = 1
+			if len(revisions) > 0 {
+				revision.ID = revisions[len(revisions)-1].ID + 1
+			}
+			revision.Timestamp = metaV1.Now()
+
+			revisions = self.bound(append(revisions, *revision), revision.Kind, revision.Name)
+		}
+
+		return revisions
+	})
+}
+
+// Removes the oldest revisions of object of given kind with given name above the size of the recorder.
+func (self *Recorder) bound(revisions []Revision, kind, name string) []Revision {
+	count := 0
+	for _, revision := range revisions {
+		if revision.Kind == kind && revision.Name == name {
+			count++
+		}
+	}
+
+	result := make([]Revision, 0, len(revisions))
+	for _, revision := range revisions {
+		if revision.Kind == kind && revision.Name == name && count > self.size {
+			count--
+			continue
+		}
+		result = append(result, revision)
+	}
+
+	return result
+}
+
+// List returns revisions of object of given kind with given name, the newest first.
+func (self *Recorder) List(client kubernetes.Interface, cluster, namespace, kind, name string) (*RevisionList,
+	error) {
+	revisions, err := self.backend.Load(client, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RevisionList{Revisions: make([]Revision, 0)}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Kind == kind && revisions[i].Name == name {
+			result.Revisions = append(result.Revisions, revisions[i])
+		}
+	}
+	result.ListMeta = api.ListMeta{TotalItems: len(result.Revisions)}
+
+	return result, nil
+}
+
+// Get returns revision of object of given kind with given name with given ID. Not found error is returned if
+// the revision does not exist or has been already removed from the history.
+func (self *Recorder) Get(client kubernetes.Interface, cluster, namespace, kind, name string, id int64) (
+	*Revision, error) {
+	list, err := self.List(client, cluster, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range list.Revisions {
+		if revision.ID == id {
+			return &revision, nil
+		}
+	}
+
+	return nil, k8sErrors.NewNotFound(schema.GroupResource{Resource: "revisions"}, fmt.Sprint(id))
+}
+
+// Recorder used to record changes made through k8sconsole. Nil if the history is disabled.
+var recorder *Recorder
+
+// SetRecorder sets recorder of changes made through k8sconsole. Passing nil disables the history.
+func SetRecorder(r *Recorder) {
+	recorder = r
+}
+
+// GetRecorder returns recorder of changes made through k8sconsole or nil if the history is disabled.
+func GetRecorder() *Recorder {
+	return recorder
+}
@@ -0,0 +1,227 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTestRevision(t *testing.T, replicas string) *Revision {
+	before := []byte(`{"kind":"Deployment","metadata":{"name":"web","namespace":"default","uid":"1",` +
+		`"resourceVersion":"1"},"spec":{"replicas":1},"status":{"replicas":1}}`)
+	after := []byte(`{"kind":"Deployment","metadata":{"name":"web","namespace":"default","uid":"1",` +
+		`"resourceVersion":"2"},"spec":{"replicas":` + replicas + `},"status":{"replicas":1}}`)
+
+	revision, err := NewRevision(OperationUpdate, "Deployment", "admin", before, after)
+	if err != nil {
+		t.Fatalf("NewRevision(): Unexpected error: %s", err)
+	}
+
+	return revision
+}
+
+func TestRecorder(t *testing.T) {
+	for _, name := range []string{BackendMemory, BackendConfigMap, BackendSecret} {
+		client := fake.NewSimpleClientset()
+		backend, err := NewBackend(name)
+		if err != nil {
+			t.Fatalf("NewBackend(%s): Unexpected error: %s", name, err)
+		}
+
+		recorder := NewRecorder(backend, 2)
+		other, err := NewRevision(OperationDelete, "Service", "admin",
+			[]byte(`{"metadata":{"name":"web","namespace":"default","uid":"2"}}`), nil)
+		if err != nil {
+			t.Fatalf("NewRevision(): Unexpected error: %s", err)
+		}
+
+		for _, revision := range []*Revision{newTestRevision(t, "2"), other, newTestRevision(t, "3"),
+			newTestRevision(t, "4")} {
+			if err := recorder.Record(client, "", revision); err != nil {
+				t.Fatalf("Record(%s): Unexpected error: %s", name, err)
+			}
+		}
+
+		list, err := recorder.List(client, "", "default", "Deployment", "web")
+		if err != nil {
+			t.Fatalf("List(%s): Unexpected error: %s", name, err)
+		}
+
+		if list.ListMeta.TotalItems != 2 || list.Revisions[0].ID != 4 || list.Revisions[1].ID != 3 {
+			t.Errorf("List(%s): Expected revisions 4 and 3 but got %+v", name, list)
+		}
+
+		deleted, err := recorder.List(client, "", "default", "Service", "web")
+		if err != nil {
+			t.Fatalf("List(%s): Unexpected error: %s", name, err)
+		}
+
+		if deleted.ListMeta.TotalItems != 1 || deleted.Revisions[0].ID != 2 || deleted.Revisions[0].UID != "2" {
+			t.Errorf("List(%s): Expected revision 2 of deleted service but got %+v", name, deleted)
+		}
+
+		if list, _ := recorder.List(client, "other", "default", "Deployment", "web"); name == BackendMemory &&
+			list.ListMeta.TotalItems != 0 {
+			t.Errorf("List(%s): Expected history of other cluster to be empty but got %+v", name, list)
+		}
+
+		if list.Revisions[0].User != "admin" || list.Revisions[0].UID != "1" ||
+			string(list.Revisions[0].After) != `{"kind":"Deployment","metadata":{"name":"web",`+
+				`"namespace":"default"},"spec":{"replicas":4}}` {
+			t.Errorf("List(%s): Unexpected revision %+v", name, list.Revisions[0])
+		}
+
+		if _, err := recorder.Get(client, "", "default", "Deployment", "web", 1); !k8sErrors.IsNotFound(err) {
+			t.Errorf("Get(%s): Expected removed revision not to be found but got %v", name, err)
+		}
+	}
+}
+
+func TestRevision_RevertObject(t *testing.T) {
+	revision := newTestRevision(t, "2")
+	raw, err := revision.RevertObject("5")
+	if err != nil {
+		t.Fatalf("RevertObject(): Unexpected error: %s", err)
+	}
+
+	object := struct {
+		Metadata map[string]string `json:"metadata"`
+		Spec     map[string]int    `json:"spec"`
+	}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		t.Fatal(err)
+	}
+
+	if object.Metadata["resourceVersion"] != "5" || object.Spec["replicas"] != 1 {
+		t.Errorf("RevertObject(): Expected object before the change with live resource version but got %s", raw)
+	}
+
+	raw, err = revision.RevertObject("")
+	if err != nil {
+		t.Fatalf("RevertObject(): Unexpected error: %s", err)
+	}
+
+	if bytes.Contains(raw, []byte("resourceVersion")) {
+		t.Errorf("RevertObject(): Expected deleted object to be created without resource version but got %s", raw)
+	}
+
+	created := &Revision{After: revision.After}
+	if _, err := created.RevertObject("5"); !k8sErrors.IsBadRequest(err) {
+		t.Errorf("RevertObject(): Expected bad request for created object but got %v", err)
+	}
+}
+
+func TestRecorder_Secret(t *testing.T) {
+	before := []byte(`{"kind":"Secret","metadata":{"name":"db","namespace":"default","uid":"2"},` +
+		`"data":{"password":"b2xk"}}`)
+	after := []byte(`{"kind":"Secret","metadata":{"name":"db","namespace":"default","uid":"2"},` +
+		`"data":{"password":"bmV3"}}`)
+
+	cases := []struct {
+		backend  string
+		redacted bool
+	}{
+		{BackendConfigMap, true},
+		{BackendSecret, false},
+		{BackendMemory, false},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		backend, _ := NewBackend(c.backend)
+		recorder := NewRecorder(backend, 2)
+		revision, err := NewRevision(OperationUpdate, "Secret", "admin", before, after)
+		if err != nil {
+			t.Fatalf("NewRevision(): Unexpected error: %s", err)
+		}
+
+		if err := recorder.Record(client, "", revision); err != nil {
+			t.Fatalf("Record(%s): Unexpected error: %s", c.backend, err)
+		}
+
+		recorded, err := recorder.Get(client, "", "default", "Secret", "db", 1)
+		if err != nil {
+			t.Fatalf("Get(%s): Unexpected error: %s", c.backend, err)
+		}
+
+		redacted := !bytes.Contains(recorded.Before, []byte("b2xk")) && !bytes.Contains(recorded.After, []byte("bmV3"))
+		if recorded.Redacted != c.redacted || redacted != c.redacted {
+			t.Errorf("Record(%s): Expected data to be redacted: %t, but got %+v", c.backend, c.redacted, recorded)
+		}
+
+		if _, err := recorded.RevertObject("5"); c.redacted != k8sErrors.IsBadRequest(err) {
+			t.Errorf("RevertObject(%s): Expected bad request for redacted revision: %t, but got %v", c.backend,
+				c.redacted, err)
+		}
+	}
+}
+
+func TestRecorder_Concurrent(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	backend, _ := NewBackend(BackendConfigMap)
+	recorder := NewRecorder(backend, 2)
+
+	count := 20
+	errs := make(chan error, count)
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		revision, err := NewRevision(OperationDelete, "Pod", "admin",
+			[]byte(fmt.Sprintf(`{"metadata":{"name":"pod-%d","namespace":"default","uid":"%d"}}`, i, i)), nil)
+		if err != nil {
+			t.Fatalf("NewRevision(): Unexpected error: %s", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- recorder.Record(client, "", revision)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Record(): Unexpected error: %s", err)
+		}
+	}
+
+	revisions, err := backend.Load(client, "", "default")
+	if err != nil {
+		t.Fatalf("Load(): Unexpected error: %s", err)
+	}
+
+	ids := make(map[int64]bool)
+	for _, revision := range revisions {
+		ids[revision.ID] = true
+	}
+	if len(revisions) != count || len(ids) != count {
+		t.Errorf("Record(): Expected %d revisions with unique IDs but got %+v", count, revisions)
+	}
+}
+
+func TestMarshalRevisions(t *testing.T) {
+	large := json.RawMessage(`"` + strings.Repeat("x", maxStoredBytes/2) + `"`)
+	revisions := []Revision{{ID: 1, After: large}, {ID: 2, After: large}, {ID: 3, After: large}}
+
+	data, err := marshalRevisions(revisions)
+	if err != nil {
+		t.Fatalf("marshalRevisions(): Unexpected error: %s", err)
+	}
+
+	stored, _ := unmarshalRevisions(data)
+	if len(stored) != 1 || stored[0].ID != 3 {
+		t.Errorf("marshalRevisions(): Expected only the newest revision to be stored but got %d revisions",
+			len(stored))
+	}
+
+	tooLarge := []Revision{{ID: 1, After: json.RawMessage(`"` + strings.Repeat("x", maxStoredBytes) + `"`)}}
+	if _, err := marshalRevisions(tooLarge); err == nil {
+		t.Error("marshalRevisions(): Expected error for revision larger than the limit")
+	}
+}
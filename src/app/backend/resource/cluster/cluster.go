@@ -28,12 +28,12 @@ type Cluster struct {
 func GetCluster(client kubernetes.Interface, dsQuery *dataselect.DataSelectQuery) (*Cluster, error) {
 	glog.Info("Getting cluster category.")
 	channels := &common.ResourceChannels{
-		NamespaceList:        common.GetNamespaceListChannel(client, 1),
-		NodeList:             common.GetNodeListChannel(client, 1),
-		PersistentVolumeList: common.GetPersistentVolumeListChannel(client, 1),
-		RoleList:             common.GetRoleListChannel(client, 1),
-		ClusterRoleList:      common.GetClusterRoleListChannel(client, 1),
-		StorageClassList:     common.GetStorageClassListChannel(client, 1),
+		NamespaceList:        common.GetNamespaceListChannelWithOptions(client, dsQuery.ListOptionsFor("namespace"), 1),
+		NodeList:             common.GetNodeListChannelWithOptions(client, dsQuery.ListOptionsFor("node"), 1),
+		PersistentVolumeList: common.GetPersistentVolumeListChannelWithOptions(client, dsQuery.ListOptionsFor("persistentvolume"), 1),
+		RoleList:             common.GetRoleListChannelWithOptions(client, dsQuery.ListOptionsFor("role"), 1),
+		ClusterRoleList:      common.GetClusterRoleListChannelWithOptions(client, dsQuery.ListOptionsFor("clusterrole"), 1),
+		StorageClassList:     common.GetStorageClassListChannelWithOptions(client, dsQuery.ListOptionsFor("storageclass"), 1),
 	}
 
 	return GetClusterFromChannels(client, channels, dsQuery)
@@ -58,7 +58,8 @@ func GetClusterFromChannels(client kubernetes.Interface, channels *common.Resour
 
 	go func() {
 		items, err := node.GetNodeListFromChannels(client, channels,
-			dataselect.NewDataSelectQuery(dsQuery.PaginationQuery, dsQuery.SortQuery, dsQuery.FilterQuery))
+			dataselect.NewDataSelectQuery(dsQuery.PaginationQuery, dsQuery.SortQuery, dsQuery.FilterQuery,
+				dsQuery.SelectorQuery))
 		errChan <- err
 		nodeChan <- items
 	}()
@@ -0,0 +1,463 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	client "k8s.io/client-go/kubernetes"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Period after which failed watch is restarted with a fresh list of resources.
+const cacheRelistPeriod = 5 * time.Second
+
+// Cache status reported to clients, see ResourceCache.Status.
+const (
+	// CacheStatusSynced means that all cached lists are kept up to date by apiserver watches.
+	CacheStatusSynced = "synced"
+	// CacheStatusStale means that at least one watch is broken and lists served from memory may be outdated
+	// until it is restarted.
+	CacheStatusStale = "stale"
+)
+
+// Resource cache used to serve lists from memory. Nil if the cache is disabled.
+var resourceCache *ResourceCache
+
+// SetResourceCache sets cache used by list channels. Passing nil disables the cache.
+func SetResourceCache(cache *ResourceCache) {
+	resourceCache = cache
+}
+
+// GetResourceCache returns cache used by list channels or nil if the cache is disabled.
+func GetResourceCache() *ResourceCache {
+	return resourceCache
+}
+
+// Describes how resource kept in the cache is listed and watched.
+type cachedResource struct {
+	// Group and plural name of the resource used in access reviews, i.e. 'apps' and 'deployments'.
+	group string
+	name  string
+
+	list  func(client client.Interface, options metaV1.ListOptions) (runtime.Object, error)
+	watch func(client client.Interface, options metaV1.ListOptions) (watch.Interface, error)
+}
+
+// Resources kept in the cache. Secrets are deliberately left out, so they are never held in memory.
+var cachedResources = []cachedResource{
+	{"", "pods",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Pods("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Pods("").Watch(o)
+		}},
+	{"", "replicationcontrollers",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().ReplicationControllers("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().ReplicationControllers("").Watch(o)
+		}},
+	{"", "services",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Services("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Services("").Watch(o)
+		}},
+	{"", "events",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Events("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Events("").Watch(o)
+		}},
+	{"", "namespaces",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Namespaces().List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Namespaces().Watch(o)
+		}},
+	{"", "nodes",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Nodes().List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Nodes().Watch(o)
+		}},
+	{"apps", "replicasets",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.AppsV1beta2().ReplicaSets("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.AppsV1beta2().ReplicaSets("").Watch(o)
+		}},
+	{"apps", "deployments",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.AppsV1beta2().Deployments("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.AppsV1beta2().Deployments("").Watch(o)
+		}},
+	{"apps", "daemonsets",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.AppsV1beta2().DaemonSets("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.AppsV1beta2().DaemonSets("").Watch(o)
+		}},
+	{"apps", "statefulsets",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.AppsV1beta2().StatefulSets("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.AppsV1beta2().StatefulSets("").Watch(o)
+		}},
+	{"batch", "jobs",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.BatchV1().Jobs("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.BatchV1().Jobs("").Watch(o)
+		}},
+	{"batch", "cronjobs",
+		func(c client.Interface, o metaV1.ListOptions) (runtime.Object, error) {
+			return c.BatchV1beta1().CronJobs("").List(o)
+		},
+		func(c client.Interface, o metaV1.ListOptions) (watch.Interface, error) {
+			return c.BatchV1beta1().CronJobs("").Watch(o)
+		}},
+}
+
+// Keeps objects of a single resource in sync with apiserver using list and watch.
+type cacheStore struct {
+	resource cachedResource
+
+	// Objects mapped by namespace and name.
+	items map[string]runtime.Object
+	// True once the first list has been received.
+	synced bool
+	// True while the watch is open.
+	connected bool
+	mux       sync.RWMutex
+}
+
+// Lists all objects and watches for changes until the watch fails or the cache is stopped. Apiserver closes
+// watches after a timeout, so closed watch is reopened from the last seen resource version, as no change has
+// been lost.
+func (self *cacheStore) listAndWatch(client client.Interface, stopCh <-chan struct{}) {
+	list, err := self.resource.list(client, api.ListEverything)
+	if err != nil {
+		glog.Errorf("Could not list %s for resource cache: %s", self.resource.name, err.Error())
+		self.setConnected(false)
+		return
+	}
+
+	listMeta, err := meta.ListAccessor(list)
+	if err != nil {
+		glog.Errorf("Could not read %s list metadata: %s", self.resource.name, err.Error())
+		return
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		glog.Errorf("Could not extract %s list items: %s", self.resource.name, err.Error())
+		return
+	}
+
+	self.replace(items)
+
+	resourceVersion := listMeta.GetResourceVersion()
+	for {
+		watcher, err := self.resource.watch(client, metaV1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			glog.Errorf("Could not watch %s for resource cache: %s", self.resource.name, err.Error())
+			self.setConnected(false)
+			return
+		}
+
+		lastResourceVersion, err := self.watch(watcher)
+		if err != nil {
+			glog.Warningf("Watch of %s for resource cache failed: %s", self.resource.name, err.Error())
+			self.setConnected(false)
+			return
+		}
+
+		// Watch closed without any event is not reopened right away, so a watch closed immediately does not
+		// hammer apiserver. Objects are listed again after relist period then.
+		if len(lastResourceVersion) == 0 {
+			return
+		}
+		resourceVersion = lastResourceVersion
+
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+	}
+}
+
+// Applies changes received by the watch until it is closed. Returns resource version of the last changed object
+// or error if the watch has failed.
+func (self *cacheStore) watch(watcher watch.Interface) (string, error) {
+	defer watcher.Stop()
+
+	resourceVersion := ""
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Added, watch.Modified:
+			self.set(event.Object)
+		case watch.Deleted:
+			self.delete(event.Object)
+		case watch.Error:
+			return "", k8sErrors.FromObject(event.Object)
+		}
+
+		if accessor, err := meta.Accessor(event.Object); err == nil {
+			resourceVersion = accessor.GetResourceVersion()
+		}
+	}
+
+	return resourceVersion, nil
+}
+
+func (self *cacheStore) replace(items []runtime.Object) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	self.items = make(map[string]runtime.Object)
+	for _, item := range items {
+		if key, err := cacheKey(item); err == nil {
+			self.items[key] = item
+		}
+	}
+
+	self.synced = true
+	self.connected = true
+}
+
+func (self *cacheStore) set(obj runtime.Object) {
+	key, err := cacheKey(obj)
+	if err != nil {
+		return
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.items[key] = obj
+}
+
+func (self *cacheStore) delete(obj runtime.Object) {
+	key, err := cacheKey(obj)
+	if err != nil {
+		return
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	delete(self.items, key)
+}
+
+func (self *cacheStore) setConnected(connected bool) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.connected = connected
+}
+
+// Returns true if the store holds objects and false if the watch is broken as well.
+func (self *cacheStore) state() (synced bool, connected bool) {
+	self.mux.RLock()
+	defer self.mux.RUnlock()
+	return self.synced, self.connected
+}
+
+// Returns copies of objects from given namespace, or all namespaces if it is empty, that match label selector.
+// Objects are ordered by namespace and name the same way as in lists returned by apiserver, so pages of the list
+// do not overlap.
+func (self *cacheStore) list(namespace string, selector labels.Selector) ([]runtime.Object, error) {
+	self.mux.RLock()
+	defer self.mux.RUnlock()
+
+	keys := make([]string, 0, len(self.items))
+	for key := range self.items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]runtime.Object, 0)
+	for _, key := range keys {
+		item := self.items[key]
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(namespace) > 0 && accessor.GetNamespace() != namespace {
+			continue
+		}
+
+		if !selector.Matches(labels.Set(accessor.GetLabels())) {
+			continue
+		}
+
+		result = append(result, item.DeepCopyObject())
+	}
+
+	return result, nil
+}
+
+func cacheKey(obj runtime.Object) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+
+	return accessor.GetNamespace() + "/" + accessor.GetName(), nil
+}
+
+// ResourceCache keeps lists of frequently used resources in memory. It is filled using insecure (or service
+// account) client, so every list served from memory is preceded by SelfSubjectAccessReview done with the
+// client of the user to keep RBAC semantics.
+type ResourceCache struct {
+	client client.Interface
+	// Host of apiserver the cache is filled from. Only clients of the same apiserver are served.
+	host   string
+	stores map[string]*cacheStore
+}
+
+// Run starts list and watch of all cached resources. It does not block.
+func (self *ResourceCache) Run(stopCh <-chan struct{}) {
+	for _, store := range self.stores {
+		store := store
+		go wait.Until(func() { store.listAndWatch(self.client, stopCh) }, cacheRelistPeriod, stopCh)
+	}
+}
+
+// Status returns CacheStatusStale if watch of any resource that is served from memory is broken
+// and CacheStatusSynced otherwise.
+func (self *ResourceCache) Status() string {
+	for _, store := range self.stores {
+		if synced, connected := store.state(); synced && !connected {
+			return CacheStatusStale
+		}
+	}
+
+	return CacheStatusSynced
+}
+
+// List fills given list with cached objects of the resource from given namespace. Returns false if the list
+// cannot be served from memory and has to be requested from apiserver, i.e. the resource is not cached yet,
+// client talks to other apiserver or options contain field selector or request a page of the list. Returns
+// forbidden error if the user is not allowed to list the resource.
+func (self *ResourceCache) List(client client.Interface, resource string, namespace string,
+	options metaV1.ListOptions, into runtime.Object) (bool, error) {
+	store, ok := self.stores[resource]
+	if !ok || len(options.FieldSelector) > 0 || options.Limit > 0 || len(options.Continue) > 0 ||
+		!self.serves(client) {
+		return false, nil
+	}
+
+	if synced, _ := store.state(); !synced {
+		return false, nil
+	}
+
+	selector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		return false, nil
+	}
+
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationV1.SelfSubjectAccessReview{
+			Spec: authorizationV1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationV1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "list",
+					Group:     store.resource.group,
+					Resource:  store.resource.name,
+				},
+			},
+		})
+	if err != nil {
+		// Let apiserver decide.
+		return false, nil
+	}
+
+	if !review.Status.Allowed {
+		reason := review.Status.Reason
+		if len(reason) == 0 {
+			reason = fmt.Sprintf("cannot list %s in namespace %q", store.resource.name, namespace)
+		}
+
+		return true, k8sErrors.NewForbidden(
+			schema.GroupResource{Group: store.resource.group, Resource: store.resource.name}, "",
+			errors.New(reason))
+	}
+
+	items, err := store.list(namespace, selector)
+	if err != nil {
+		return false, nil
+	}
+
+	return true, meta.SetList(into, items)
+}
+
+// Returns true if given client is the client cache is filled with or it talks to the same apiserver.
+func (self *ResourceCache) serves(c client.Interface) bool {
+	if c == self.client {
+		return true
+	}
+
+	return len(self.host) > 0 && clientHost(c) == self.host
+}
+
+// Returns host of apiserver given client talks to, or empty string if it cannot be determined, i.e. for fake
+// clients used in tests.
+func clientHost(c client.Interface) string {
+	clientset, ok := c.(*client.Clientset)
+	if !ok || clientset == nil {
+		return ""
+	}
+
+	return clientset.CoreV1().RESTClient().Get().URL().Host
+}
+
+// NewResourceCache creates cache of frequently listed resources filled using given client. Call Run to start
+// the cache and SetResourceCache to make list channels use it.
+func NewResourceCache(client client.Interface) *ResourceCache {
+	cache := &ResourceCache{
+		client: client,
+		host:   clientHost(client),
+		stores: make(map[string]*cacheStore),
+	}
+
+	for _, resource := range cachedResources {
+		cache.stores[resource.name] = &cacheStore{resource: resource, items: make(map[string]runtime.Object)}
+	}
+
+	return cache
+}
+
+// Lists resource from the cache if it is enabled. Returns false if the list has to be requested from apiserver.
+func listFromCache(client client.Interface, resource string, namespace string, options metaV1.ListOptions,
+	into runtime.Object) (bool, error) {
+	if resourceCache == nil {
+		return false, nil
+	}
+
+	return resourceCache.List(client, resource, namespace, options, into)
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	authorizationV1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"testing"
+	"time"
+)
+
+func newTestPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func TestResourceCache_List(t *testing.T) {
+	client := fake.NewSimpleClientset(newTestPod("allowed", "pod-1"), newTestPod("denied", "pod-2"))
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authorizationV1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "allowed"
+		return true, review, nil
+	})
+
+	cache := NewResourceCache(client)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	cache.Run(stopCh)
+
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		synced, connected := cache.stores["pods"].state()
+		return synced && connected, nil
+	})
+	if err != nil {
+		t.Fatalf("Run(): Expected pods to be synced but got %s", err)
+	}
+
+	SetResourceCache(cache)
+	defer SetResourceCache(nil)
+
+	if _, err := client.CoreV1().Pods("allowed").Create(newTestPod("allowed", "pod-3")); err != nil {
+		t.Fatal(err)
+	}
+
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		channel := GetPodListChannel(client, NewNamespaceQuery([]string{"allowed"}), 1)
+		list, err := <-channel.List, <-channel.Error
+		return err == nil && len(list.Items) == 2, err
+	})
+	if err != nil {
+		t.Errorf("GetPodListChannel(): Expected 2 pods to be served from cache but got %s", err)
+	}
+
+	channel := GetPodListChannel(client, NewNamespaceQuery([]string{"denied"}), 1)
+	<-channel.List
+	if err := <-channel.Error; !k8sErrors.IsForbidden(err) {
+		t.Errorf("GetPodListChannel(): Expected forbidden error but got %v", err)
+	}
+
+	// Pages are not served from cache, so the cache does not deny them.
+	channel = GetPodListChannelWithOptions(client, NewNamespaceQuery([]string{"denied"}),
+		metaV1.ListOptions{Limit: 1}, 1)
+	<-channel.List
+	if err := <-channel.Error; err != nil {
+		t.Errorf("GetPodListChannelWithOptions(): Expected page to be requested from apiserver but got %v", err)
+	}
+
+	if status := cache.Status(); status != CacheStatusSynced {
+		t.Errorf("Status(): Expected %s but got %s", CacheStatusSynced, status)
+	}
+}
+
+func TestCacheStore_List(t *testing.T) {
+	store := NewResourceCache(fake.NewSimpleClientset()).stores["pods"]
+	store.replace([]runtime.Object{newTestPod("b", "pod-1"), newTestPod("a", "pod-2"), newTestPod("a", "pod-1")})
+
+	items, err := store.list("", labels.Everything())
+	if err != nil {
+		t.Fatalf("list(): Unexpected error: %s", err)
+	}
+
+	expected := []string{"a/pod-1", "a/pod-2", "b/pod-1"}
+	for i, item := range items {
+		if key, _ := cacheKey(item); key != expected[i] {
+			t.Errorf("list(): Expected %s at position %d but got %s", expected[i], i, key)
+		}
+	}
+}
+
+func TestCacheStore_ListAndWatch(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	watchers := make(chan *watch.FakeWatcher, 2)
+	versions := make(chan string, cap(watchers))
+	client.PrependWatchReactor("pods", func(action core.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFake()
+		versions <- action.(core.WatchAction).GetWatchRestrictions().ResourceVersion
+		watchers <- watcher
+		return true, watcher, nil
+	})
+
+	store := NewResourceCache(client).stores["pods"]
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	done := make(chan struct{})
+	go func() {
+		store.listAndWatch(client, stopCh)
+		close(done)
+	}()
+
+	pod := newTestPod("default", "pod-1")
+	pod.ResourceVersion = "5"
+	watcher := <-watchers
+	watcher.Add(pod)
+	// Apiserver closes watch after a timeout.
+	watcher.Stop()
+
+	watcher = <-watchers
+	// Fake clientset drops resource version of lists, so the first watch is opened without it.
+	if version := <-versions + "," + <-versions; version != ",5" {
+		t.Errorf("listAndWatch(): Expected watch to be reopened from resource version 5 but got %s", version)
+	}
+
+	if synced, connected := store.state(); !synced || !connected {
+		t.Errorf("state(): Expected store to stay connected after watch has been closed")
+	}
+
+	watcher.Error(&metaV1.Status{Status: metaV1.StatusFailure, Reason: metaV1.StatusReasonExpired})
+	<-done
+
+	if _, connected := store.state(); connected {
+		t.Errorf("state(): Expected store to be disconnected after watch has failed")
+	}
+}
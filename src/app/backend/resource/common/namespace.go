@@ -32,6 +32,12 @@ func (n *NamespaceQuery) ToRequestParam() string {
 	return api.NamespaceAll
 }
 
+// IsMultiNamespace returns true if the query selects more than one but not all namespaces. Objects of such
+// queries are listed from all namespaces and filtered in memory.
+func (n *NamespaceQuery) IsMultiNamespace() bool {
+	return len(n.namespaces) > 1
+}
+
 // Matches returns true when the given namespace matches this query.
 func (n *NamespaceQuery) Matches(namespace string) bool {
 	if len(n.namespaces) == 0 {
@@ -107,6 +107,12 @@ type ReplicationControllerListChannel struct {
 // and errors that both must be read numReads time.
 func GetReplicationControllerListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) ReplicationControllerListChannel {
+	return GetReplicationControllerListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetReplicationControllerListChannelWithOptions is the GetReplicationControllerListChannel plus list options.
+func GetReplicationControllerListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) ReplicationControllerListChannel {
 
 	channel := ReplicationControllerListChannel{
 		List: 	make(chan *v1.ReplicationControllerList, numReads),
@@ -114,7 +120,11 @@ func GetReplicationControllerListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.CoreV1().ReplicationControllers(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list := new(v1.ReplicationControllerList)
+		served, err := listFromCache(client, "replicationcontrollers", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.CoreV1().ReplicationControllers(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []v1.ReplicationController
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -155,7 +165,11 @@ func GetReplicaSetListChannelWithOptions(client client.Interface, nsQuery *Names
 	}
 
 	go func() {
-		list, err := client.AppsV1beta2().ReplicaSets(nsQuery.ToRequestParam()).List(options)
+		list := new(apps.ReplicaSetList)
+		served, err := listFromCache(client, "replicasets", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.AppsV1beta2().ReplicaSets(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []apps.ReplicaSet
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -182,6 +196,12 @@ type DeploymentListChannel struct {
 // that both must be read numReads times.
 func GetDeploymentListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) DeploymentListChannel {
+	return GetDeploymentListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetDeploymentListChannelWithOptions is the GetDeploymentListChannel plus list options.
+func GetDeploymentListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) DeploymentListChannel {
 
 	channel := DeploymentListChannel{
 		List:		make(chan *apps.DeploymentList, numReads),
@@ -189,7 +209,11 @@ func GetDeploymentListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.AppsV1beta2().Deployments(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list := new(apps.DeploymentList)
+		served, err := listFromCache(client, "deployments", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.AppsV1beta2().Deployments(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []apps.Deployment
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -216,6 +240,12 @@ type DaemonSetListChannel struct {
 // numReads times.
 func GetDaemonSetListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) DaemonSetListChannel {
+	return GetDaemonSetListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetDaemonSetListChannelWithOptions is the GetDaemonSetListChannel plus list options.
+func GetDaemonSetListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) DaemonSetListChannel {
 
 	channel := DaemonSetListChannel{
 		List: 	make(chan *apps.DaemonSetList, numReads),
@@ -223,7 +253,11 @@ func GetDaemonSetListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.AppsV1beta2().DaemonSets(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list := new(apps.DaemonSetList)
+		served, err := listFromCache(client, "daemonsets", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.AppsV1beta2().DaemonSets(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []apps.DaemonSet
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -249,13 +283,23 @@ type JobListChannel struct {
 // GetJobListChannel returns a pair of channels to a Job list and errors that both must be read numReads times.
 func GetJobListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) JobListChannel {
+	return GetJobListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetJobListChannelWithOptions is the GetJobListChannel plus list options.
+func GetJobListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) JobListChannel {
 	channel := JobListChannel{
 		List:  make(chan *batch.JobList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.BatchV1().Jobs(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list := new(batch.JobList)
+		served, err := listFromCache(client, "jobs", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.BatchV1().Jobs(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []batch.Job
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -280,13 +324,23 @@ type CronJobListChannel struct {
 
 // GetCronJobListChannel returns a pair of channels to a Cron Job list and errors that both must be read numReads times.
 func GetCronJobListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) CronJobListChannel {
+	return GetCronJobListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetCronJobListChannelWithOptions is the GetCronJobListChannel plus list options.
+func GetCronJobListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) CronJobListChannel {
 	channel := CronJobListChannel{
 		List:  make(chan *batch2.CronJobList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.BatchV1beta1().CronJobs(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list := new(batch2.CronJobList)
+		served, err := listFromCache(client, "cronjobs", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.BatchV1beta1().CronJobs(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []batch2.CronJob
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -313,13 +367,23 @@ type ServiceListChannel struct {
 // must be read numReads times.
 func GetServiceListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) ServiceListChannel {
+	return GetServiceListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetServiceListChannelWithOptions is the GetServiceListChannel plus list options.
+func GetServiceListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) ServiceListChannel {
 
 	channel := ServiceListChannel{
 		List:  make(chan *v1.ServiceList, numReads),
 		Error: make(chan error, numReads),
 	}
 	go func() {
-		list, err := client.CoreV1().Services(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list := new(v1.ServiceList)
+		served, err := listFromCache(client, "services", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.CoreV1().Services(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []v1.Service
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -376,13 +440,19 @@ type IngressListChannel struct {
 // must be read numReads times.
 func GetIngressListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) IngressListChannel {
+	return GetIngressListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetIngressListChannelWithOptions is the GetIngressListChannel plus list options.
+func GetIngressListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) IngressListChannel {
 
 	channel := IngressListChannel{
 		List:  make(chan *extensions.IngressList, numReads),
 		Error: make(chan error, numReads),
 	}
 	go func() {
-		list, err := client.ExtensionsV1beta1().Ingresses(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list, err := client.ExtensionsV1beta1().Ingresses(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []extensions.Ingress
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -422,7 +492,11 @@ func GetPodListChannelWithOptions(client client.Interface, nsQuery *NamespaceQue
 	}
 
 	go func() {
-		list, err := client.CoreV1().Pods(nsQuery.ToRequestParam()).List(options)
+		list := new(v1.PodList)
+		served, err := listFromCache(client, "pods", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.CoreV1().Pods(nsQuery.ToRequestParam()).List(options)
+		}
 		var filterItems []v1.Pod
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -460,7 +534,11 @@ func GetEventListChannelWithOptions(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.CoreV1().Events(nsQuery.ToRequestParam()).List(options)
+		list := new(v1.EventList)
+		served, err := listFromCache(client, "events", nsQuery.ToRequestParam(), options, list)
+		if !served {
+			list, err = client.CoreV1().Events(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []v1.Event
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -486,6 +564,12 @@ type LimitRangeListChannel struct {
 // both must be read numReads times.
 func GetLimitRangeListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) LimitRangeListChannel {
+	return GetLimitRangeListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetLimitRangeListChannelWithOptions is the GetLimitRangeListChannel plus list options.
+func GetLimitRangeListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) LimitRangeListChannel {
 
 	channel := LimitRangeListChannel{
 		List:  make(chan *v1.LimitRangeList, numReads),
@@ -493,7 +577,7 @@ func GetLimitRangeListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	}
 
 	go func() {
-		list, err := client.CoreV1().LimitRanges(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list, err := client.CoreV1().LimitRanges(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -512,13 +596,23 @@ type NodeListChannel struct {
 // GetNodeListChannel returns a pair of channels to a Node list and errors that both must be read
 // numReads times.
 func GetNodeListChannel(client client.Interface, numReads int) NodeListChannel {
+	return GetNodeListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetNodeListChannelWithOptions is the GetNodeListChannel plus list options.
+func GetNodeListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
+	numReads int) NodeListChannel {
 	channel := NodeListChannel{
 		List:  make(chan *v1.NodeList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.CoreV1().Nodes().List(api.ListEverything)
+		list := new(v1.NodeList)
+		served, err := listFromCache(client, "nodes", "", options, list)
+		if !served {
+			list, err = client.CoreV1().Nodes().List(options)
+		}
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -537,13 +631,23 @@ type NamespaceListChannel struct {
 // GetNamespaceListChannel returns a pair of channels to a Namespace list and errors that
 // must be read numReads times.
 func GetNamespaceListChannel(client client.Interface, numReads int) NamespaceListChannel {
+	return GetNamespaceListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetNamespaceListChannelWithOptions is the GetNamespaceListChannel plus list options.
+func GetNamespaceListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
+	numReads int) NamespaceListChannel {
 	channel := NamespaceListChannel{
 		List: 	make(chan *v1.NamespaceList, numReads),
 		Error: 	make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.CoreV1().Namespaces().List(api.ListEverything)
+		list := new(v1.NamespaceList)
+		served, err := listFromCache(client, "namespaces", "", options, list)
+		if !served {
+			list, err = client.CoreV1().Namespaces().List(options)
+		}
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -563,13 +667,23 @@ type StatefulSetListChannel struct {
 // numReads times.
 func GetStatefulSetListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) StatefulSetListChannel {
+	return GetStatefulSetListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetStatefulSetListChannelWithOptions is the GetStatefulSetListChannel plus list options.
+func GetStatefulSetListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) StatefulSetListChannel {
 	channel := StatefulSetListChannel{
 		List:  make(chan *apps.StatefulSetList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		statefulSets, err := client.AppsV1beta2().StatefulSets(nsQuery.ToRequestParam()).List(api.ListEverything)
+		statefulSets := new(apps.StatefulSetList)
+		served, err := listFromCache(client, "statefulsets", nsQuery.ToRequestParam(), options, statefulSets)
+		if !served {
+			statefulSets, err = client.AppsV1beta2().StatefulSets(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []apps.StatefulSet
 		for _, item := range statefulSets.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -596,6 +710,12 @@ type ConfigMapListChannel struct {
 // numReads times.
 func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) ConfigMapListChannel {
+	return GetConfigMapListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetConfigMapListChannelWithOptions is the GetConfigMapListChannel plus list options.
+func GetConfigMapListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) ConfigMapListChannel {
 
 	channel := ConfigMapListChannel{
 		List:  make(chan *v1.ConfigMapList, numReads),
@@ -603,7 +723,7 @@ func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	}
 
 	go func() {
-		list, err := client.CoreV1().ConfigMaps(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list, err := client.CoreV1().ConfigMaps(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []v1.ConfigMap
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -630,6 +750,12 @@ type SecretListChannel struct {
 // both must be read numReads times.
 func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) SecretListChannel {
+	return GetSecretListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetSecretListChannelWithOptions is the GetSecretListChannel plus list options.
+func GetSecretListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) SecretListChannel {
 
 	channel := SecretListChannel{
 		List:  make(chan *v1.SecretList, numReads),
@@ -637,7 +763,7 @@ func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	}
 
 	go func() {
-		list, err := client.CoreV1().Secrets(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list, err := client.CoreV1().Secrets(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []v1.Secret
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -663,6 +789,12 @@ type PersistentVolumeListChannel struct {
 // GetPersistentVolumeListChannel returns a pair of channels to a PersistentVolume list and errors
 // that both must be read numReads times.
 func GetPersistentVolumeListChannel(client client.Interface,
+	numReads int) PersistentVolumeListChannel {
+	return GetPersistentVolumeListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetPersistentVolumeListChannelWithOptions is the GetPersistentVolumeListChannel plus list options.
+func GetPersistentVolumeListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
 	numReads int) PersistentVolumeListChannel {
 	channel := PersistentVolumeListChannel{
 		List:  make(chan *v1.PersistentVolumeList, numReads),
@@ -670,7 +802,7 @@ func GetPersistentVolumeListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.CoreV1().PersistentVolumes().List(api.ListEverything)
+		list, err := client.CoreV1().PersistentVolumes().List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -690,6 +822,12 @@ type PersistentVolumeClaimListChannel struct {
 // and errors that both must be read numReads times.
 func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) PersistentVolumeClaimListChannel {
+	return GetPersistentVolumeClaimListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetPersistentVolumeClaimListChannelWithOptions is the GetPersistentVolumeClaimListChannel plus list options.
+func GetPersistentVolumeClaimListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) PersistentVolumeClaimListChannel {
 
 	channel := PersistentVolumeClaimListChannel{
 		List:  make(chan *v1.PersistentVolumeClaimList, numReads),
@@ -697,7 +835,7 @@ func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *Names
 	}
 
 	go func() {
-		list, err := client.CoreV1().PersistentVolumeClaims(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list, err := client.CoreV1().PersistentVolumeClaims(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -717,6 +855,12 @@ type ResourceQuotaListChannel struct {
 // both must be read numReads times.
 func GetResourceQuotaListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) ResourceQuotaListChannel {
+	return GetResourceQuotaListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetResourceQuotaListChannelWithOptions is the GetResourceQuotaListChannel plus list options.
+func GetResourceQuotaListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) ResourceQuotaListChannel {
 
 	channel := ResourceQuotaListChannel{
 		List:  make(chan *v1.ResourceQuotaList, numReads),
@@ -724,7 +868,7 @@ func GetResourceQuotaListChannel(client client.Interface, nsQuery *NamespaceQuer
 	}
 
 	go func() {
-		list, err := client.CoreV1().ResourceQuotas(nsQuery.ToRequestParam()).List(api.ListEverything)
+		list, err := client.CoreV1().ResourceQuotas(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -744,6 +888,12 @@ type HorizontalPodAutoscalerListChannel struct {
 // both must be read numReads times.
 func GetHorizontalPodAutoscalerListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) HorizontalPodAutoscalerListChannel {
+	return GetHorizontalPodAutoscalerListChannelWithOptions(client, nsQuery, api.ListEverything, numReads)
+}
+
+// GetHorizontalPodAutoscalerListChannelWithOptions is the GetHorizontalPodAutoscalerListChannel plus list options.
+func GetHorizontalPodAutoscalerListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) HorizontalPodAutoscalerListChannel {
 	channel := HorizontalPodAutoscalerListChannel{
 		List:  make(chan *autoscaling.HorizontalPodAutoscalerList, numReads),
 		Error: make(chan error, numReads),
@@ -751,7 +901,7 @@ func GetHorizontalPodAutoscalerListChannel(client client.Interface, nsQuery *Nam
 
 	go func() {
 		list, err := client.AutoscalingV1().HorizontalPodAutoscalers(nsQuery.ToRequestParam()).
-			List(api.ListEverything)
+			List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -770,13 +920,19 @@ type StorageClassListChannel struct {
 // GetStorageClassListChannel returns a pair of channels to a storage class list and
 // errors that both must be read numReads times.
 func GetStorageClassListChannel(client client.Interface, numReads int) StorageClassListChannel {
+	return GetStorageClassListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetStorageClassListChannelWithOptions is the GetStorageClassListChannel plus list options.
+func GetStorageClassListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
+	numReads int) StorageClassListChannel {
 	channel := StorageClassListChannel{
 		List:  make(chan *storage.StorageClassList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.StorageV1().StorageClasses().List(api.ListEverything)
+		list, err := client.StorageV1().StorageClasses().List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -795,13 +951,19 @@ type RoleListChannel struct {
 // GetRoleListChannel returns a pair of channels to a Role list for a namespace and errors that
 // both must be read numReads times.
 func GetRoleListChannel(client client.Interface, numReads int) RoleListChannel {
+	return GetRoleListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetRoleListChannelWithOptions is the GetRoleListChannel plus list options.
+func GetRoleListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
+	numReads int) RoleListChannel {
 	channel := RoleListChannel{
 		List:  make(chan *rbac.RoleList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.RbacV1().Roles("").List(api.ListEverything)
+		list, err := client.RbacV1().Roles("").List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -820,13 +982,19 @@ type ClusterRoleListChannel struct {
 // GetClusterRoleListChannel returns a pair of channels to a ClusterRole list and errors that
 // both must be read numReads times.
 func GetClusterRoleListChannel(client client.Interface, numReads int) ClusterRoleListChannel {
+	return GetClusterRoleListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetClusterRoleListChannelWithOptions is the GetClusterRoleListChannel plus list options.
+func GetClusterRoleListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
+	numReads int) ClusterRoleListChannel {
 	channel := ClusterRoleListChannel{
 		List:  make(chan *rbac.ClusterRoleList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.RbacV1().ClusterRoles().List(api.ListEverything)
+		list, err := client.RbacV1().ClusterRoles().List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -845,13 +1013,19 @@ type RoleBindingListChannel struct {
 // GetRoleBindingListChannel returns a pair of channels to a RoleBinding list for a namespace and errors that
 // both must be read numReads times.
 func GetRoleBindingListChannel(client client.Interface, numReads int) RoleBindingListChannel {
+	return GetRoleBindingListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetRoleBindingListChannelWithOptions is the GetRoleBindingListChannel plus list options.
+func GetRoleBindingListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
+	numReads int) RoleBindingListChannel {
 	channel := RoleBindingListChannel{
 		List:  make(chan *rbac.RoleBindingList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.RbacV1().RoleBindings("").List(api.ListEverything)
+		list, err := client.RbacV1().RoleBindings("").List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -870,6 +1044,12 @@ type ClusterRoleBindingListChannel struct {
 // GetClusterRoleBindingListChannel returns a pair of channels to a ClusterRoleBinding list and
 // errors that both must be read numReads times.
 func GetClusterRoleBindingListChannel(client client.Interface,
+	numReads int) ClusterRoleBindingListChannel {
+	return GetClusterRoleBindingListChannelWithOptions(client, api.ListEverything, numReads)
+}
+
+// GetClusterRoleBindingListChannelWithOptions is the GetClusterRoleBindingListChannel plus list options.
+func GetClusterRoleBindingListChannelWithOptions(client client.Interface, options metaV1.ListOptions,
 	numReads int) ClusterRoleBindingListChannel {
 	channel := ClusterRoleBindingListChannel{
 		List:  make(chan *rbac.ClusterRoleBindingList, numReads),
@@ -877,7 +1057,7 @@ func GetClusterRoleBindingListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.RbacV1().ClusterRoleBindings().List(api.ListEverything)
+		list, err := client.RbacV1().ClusterRoleBindings().List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -885,4 +1065,4 @@ func GetClusterRoleBindingListChannel(client client.Interface,
 	}()
 
 	return channel
-}
\ No newline at end of file
+}
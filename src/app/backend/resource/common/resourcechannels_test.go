@@ -0,0 +1,37 @@
+package common
+
+import (
+	apps "k8s.io/api/apps/v1beta2"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"testing"
+)
+
+func TestGetDeploymentListChannelWithOptions(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&apps.Deployment{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "payments",
+			Labels: map[string]string{"app": "payments", "tier": "backend"}}},
+		&apps.Deployment{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "payments-canary",
+			Labels: map[string]string{"app": "payments", "tier": "canary"}}},
+		&apps.Deployment{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "frontend",
+			Labels: map[string]string{"app": "frontend"}}},
+	)
+
+	options := metaV1.ListOptions{LabelSelector: "app=payments,tier!=canary"}
+	channel := GetDeploymentListChannelWithOptions(client, NewOneNamespaceQuery("default"), options, 1)
+	list := <-channel.List
+	if err := <-channel.Error; err != nil {
+		t.Fatalf("GetDeploymentListChannelWithOptions(): Unexpected error: %s", err)
+	}
+
+	if len(list.Items) != 1 || list.Items[0].Name != "payments" {
+		t.Errorf("GetDeploymentListChannelWithOptions(): Expected only payments deployment but got %v", list.Items)
+	}
+
+	action := client.Actions()[0].(core.ListAction)
+	if selector := action.GetListRestrictions().Labels.String(); selector != "app=payments,tier!=canary" {
+		t.Errorf("GetDeploymentListChannelWithOptions(): Expected selector to be sent to apiserver but got %q",
+			selector)
+	}
+}
@@ -27,9 +27,9 @@ func GetConfig(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 
 	glog.Info("Getting config category")
 	channels := &common.ResourceChannels{
-		ConfigMapList:             common.GetConfigMapListChannel(client, nsQuery, 1),
-		SecretList:                common.GetSecretListChannel(client, nsQuery, 1),
-		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client, nsQuery, 1),
+		ConfigMapList:             common.GetConfigMapListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("configmap"), 1),
+		SecretList:                common.GetSecretListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("secret"), 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("persistentvolumeclaim"), 1),
 	}
 
 	return GetConfigFromChannels(channels, dsQuery, nsQuery)
@@ -34,7 +34,7 @@ func GetConfigMapList(client kubernetes.Interface, nsQuery *common.NamespaceQuer
 	dsQuery *dataselect.DataSelectQuery) (*ConfigMapList, error) {
 	glog.Infof("Getting list config maps in the namespace %s", nsQuery.ToRequestParam())
 	channels := &common.ResourceChannels{
-		ConfigMapList: common.GetConfigMapListChannel(client, nsQuery, 1),
+		ConfigMapList: common.GetConfigMapListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 	}
 	return GetConfigMapListFromChannels(channels, dsQuery)
 }
@@ -49,7 +49,9 @@ func GetConfigMapListFromChannels(channels *common.ResourceChannels, dsQuery *da
 		return nil, criticalError
 	}
 
-	return toConfigMapList(configMaps.Items, nonCriticalErrors, dsQuery), nil
+	result := toConfigMapList(configMaps.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = configMaps.Continue
+	return result, nil
 }
 
 func toConfigMapList(configMaps []v1.ConfigMap, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *ConfigMapList {
@@ -106,14 +106,14 @@ func GetCronJobCompletedJobs(client kubernetes.Interface,	dsQuery *dataselect.Da
 	return job.ToJobList(jobs.Items, pods.Items, events.Items, nonCriticalErrors, dsQuery), nil
 }
 
-// TriggerCronJob manually triggers a cron job and creates a new job.
+// TriggerCronJob manually triggers a cron job and returns the created job.
 func TriggerCronJob(client kubernetes.Interface,
-	namespace, name string) error {
+	namespace, name string) (*batch.Job, error) {
 
 	cronJob, err := client.BatchV1beta1().CronJobs(namespace).Get(name, metaV1.GetOptions{})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	annotations := make(map[string]string)
@@ -142,13 +142,13 @@ func TriggerCronJob(client kubernetes.Interface,
 		Spec: cronJob.Spec.JobTemplate.Spec,
 	}
 
-	_, err = client.BatchV1().Jobs(namespace).Create(jobToCreate)
+	job, err := client.BatchV1().Jobs(namespace).Create(jobToCreate)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return job, nil
 }
 
 func filterJobsByOwnerUID(UID types.UID, jobs []batch.Job) (matchingJobs []batch.Job) {
@@ -40,7 +40,7 @@ func GetCronJobList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 	glog.Info("Getting list of all cron jobs in the cluster")
 
 	channels := &common.ResourceChannels{
-		CronJobList: common.GetCronJobListChannel(client, nsQuery, 1),
+		CronJobList: common.GetCronJobListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 	}
 
 	return GetCronJobListFromChannels(channels, dsQuery)
@@ -59,6 +59,8 @@ func GetCronJobListFromChannels(channels *common.ResourceChannels, dsQuery *data
 	}
 
 	cronJobList := toCronJobList(cronJobs.Items, nonCriticalErrors, dsQuery)
+
+	cronJobList.ListMeta.Continue = cronJobs.Continue
 	cronJobList.Status = getStatus(cronJobs)
 	return cronJobList, nil
 }
@@ -42,7 +42,7 @@ type DaemonSet struct {
 func GetDaemonSetList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*DaemonSetList, error) {
 	channels := &common.ResourceChannels{
-		DaemonSetList: common.GetDaemonSetListChannel(client, nsQuery, 1),
+		DaemonSetList: common.GetDaemonSetListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		ServiceList:   common.GetServiceListChannel(client, nsQuery, 1),
 		PodList:       common.GetPodListChannel(client, nsQuery, 1),
 		EventList:     common.GetEventListChannel(client, nsQuery, 1),
@@ -78,6 +78,8 @@ func GetDaemonSetListFromChannels(channels *common.ResourceChannels, dsQuery *da
 	}
 
 	dsList := toDaemonSetList(daemonSets.Items, pods.Items, events.Items, nonCriticalErrors, dsQuery)
+
+	dsList.ListMeta.Continue = daemonSets.Continue
 	dsList.Status = getStatus(daemonSets, pods.Items, events.Items)
 	return dsList, nil
 }
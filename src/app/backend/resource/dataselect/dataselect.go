@@ -69,12 +69,26 @@ func (self *DataSelector) Filter() *DataSelector {
 				continue
 			}
 
-			if !v.Contains(filterBy.Value) {
+			// Raw values are parsed as the type of the property, so properties other than strings do not panic.
+			value := filterBy.Value
+			if raw, ok := value.(StdComparableString); ok {
+				var err error
+				if value, err = parseComparableValue(v, string(raw)); err != nil {
+					matches = false
+					continue
+				}
+			}
+
+			if !v.Contains(value) {
 				matches = false
 				continue
 			}
 		}
 
+		if matches && self.DataSelectQuery.FilterQuery.Expression != nil {
+			matches = self.DataSelectQuery.FilterQuery.Expression.Matches(c)
+		}
+
 		if matches {
 			filteredList = append(filteredList, c)
 		}
@@ -87,12 +101,15 @@ func (self *DataSelector) Filter() *DataSelector {
 // Paginates the data inside
 func (self *DataSelector) Paginate() *DataSelector {
 	pQuery := self.DataSelectQuery.PaginationQuery
-	// return all items if provided settings do not meet requirements
-	if !pQuery.IsValidPagination() {
+	dataList := self.GenericDataList
+	// return all items if provided settings do not meet requirements or apiserver has already paginated them.
+	// Apiserver may ignore the limit, i.e. when the list is served from its watch cache, so lists with more
+	// items than requested are paginated in memory.
+	if !pQuery.IsValidPagination() ||
+		(self.DataSelectQuery.IsChunked() && len(dataList) <= pQuery.ItemsPerPage) {
 		return self
 	}
 
-	dataList := self.GenericDataList
 	startIndex, endIndex := pQuery.GetPaginationSettings(len(dataList))
 
 	// return empty if required page does not exist
@@ -112,7 +129,12 @@ func GenericDataSelect(dataList []DataCell, dsQuery *DataSelectQuery) []DataCell
 		DataSelectQuery: dsQuery,
 	}
 
-	return SelectableData.Sort().Paginate().GenericDataList
+	sorted := SelectableData.Sort()
+	if dsQuery.ExportQuery != nil {
+		dsQuery.ExportQuery.collect(sorted.GenericDataList)
+	}
+
+	return sorted.Paginate().GenericDataList
 }
 
 // GenericDataSelectWithFilter takes a list of DataCells and DtaSelectQuery and returns selected data.
@@ -124,6 +146,11 @@ func GenericDataSelectWithFilter(dataList []DataCell, dsQuery *DataSelectQuery)
 
 	filtered := SelectableData.Filter()
 	filteredTotal := len(filtered.GenericDataList)
-	processed := filtered.Sort().Paginate()
+	sorted := filtered.Sort()
+	if dsQuery.ExportQuery != nil {
+		dsQuery.ExportQuery.collect(sorted.GenericDataList)
+	}
+
+	processed := sorted.Paginate()
 	return processed.GenericDataList, filteredTotal
 }
\ No newline at end of file
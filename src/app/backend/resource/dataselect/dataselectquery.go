@@ -1,22 +1,70 @@
 package dataselect
 
+import metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 type DataSelectQuery struct {
 	PaginationQuery 	*PaginationQuery
 	SortQuery					*SortQuery
 	FilterQuery				*FilterQuery
+	SelectorQuery			*SelectorQuery
+	// ExportQuery collects data cells of exported list. Nil if the list is not exported.
+	ExportQuery				*ExportQuery
 }
 
 // NoDataSelect is an option for no data select (same data will be returned).
-var NoDataSelect = NewDataSelectQuery(NoPagination, NoSort, NoFilter)
+var NoDataSelect = NewDataSelectQuery(NoPagination, NoSort, NoFilter, NoSelector)
 
 // DefaultDataSelect downloads first 10 items from page 1 with no sort.
-var DefaultDataSelect = NewDataSelectQuery(DefaultPagination, NoSort, NoFilter)
+var DefaultDataSelect = NewDataSelectQuery(DefaultPagination, NoSort, NoFilter, NoSelector)
 
 //  NewDataSelectQuery creates DataSelectQuery object from data select queries
-func NewDataSelectQuery(paginationQuery *PaginationQuery, sortQuery *SortQuery, filterQuery *FilterQuery) *DataSelectQuery {
+func NewDataSelectQuery(paginationQuery *PaginationQuery, sortQuery *SortQuery, filterQuery *FilterQuery,
+	selectorQuery *SelectorQuery) *DataSelectQuery {
 	return &DataSelectQuery{
 		PaginationQuery: paginationQuery,
 		SortQuery: sortQuery,
 		FilterQuery: filterQuery,
+		SelectorQuery: selectorQuery,
+	}
+}
+
+// ListOptions returns list options that pass selectors of the query to apiserver, and limit and continue token
+// if the list is chunked. All objects are selected if the query is nil.
+func (self *DataSelectQuery) ListOptions() metaV1.ListOptions {
+	if self == nil {
+		return NoSelector.ToListOptions()
 	}
+
+	options := self.SelectorQuery.ToListOptions()
+	if self.IsChunked() {
+		options.Limit = int64(self.PaginationQuery.ItemsPerPage)
+		options.Continue = self.PaginationQuery.Continue
+	}
+
+	return options
+}
+
+// ListOptionsFor returns list options of given kind listed in a view of several kinds. Selectors of the query are
+// passed only if they apply to the kind. Auxiliary lists, i.e. pods used to compute status of workloads, should
+// be listed with api.ListEverything instead.
+func (self *DataSelectQuery) ListOptionsFor(kind string) metaV1.ListOptions {
+	if self == nil || !self.SelectorQuery.AppliesTo(kind) {
+		return NoSelector.ToListOptions()
+	}
+
+	return self.ListOptions()
+}
+
+// IsChunked returns true if the list is paginated by apiserver. Chunked pagination falls back to pagination in
+// memory when the list is sorted or filtered, as both need all objects of the list.
+func (self *DataSelectQuery) IsChunked() bool {
+	if self == nil || self.PaginationQuery == nil || !self.PaginationQuery.Chunked ||
+		self.PaginationQuery.ItemsPerPage <= 0 {
+		return false
+	}
+
+	sorted := self.SortQuery != nil && len(self.SortQuery.SortByList) > 0
+	filtered := self.FilterQuery != nil && (len(self.FilterQuery.FilterByList) > 0 ||
+		self.FilterQuery.Expression != nil)
+	return !sorted && !filtered
 }
@@ -0,0 +1,58 @@
+package dataselect
+
+import (
+	"testing"
+)
+
+func TestDataSelectQuery_ListOptions(t *testing.T) {
+	chunked := NewChunkedPaginationQuery(2, 1, "token")
+	cases := []struct {
+		query         *DataSelectQuery
+		limit         int64
+		continueToken string
+		selected      int
+	}{
+		{NewDataSelectQuery(chunked, NoSort, NoFilter, NoSelector), 2, "token", 1},
+		{NewDataSelectQuery(NewChunkedPaginationQuery(3, 1, "token"), NoSort, NoFilter, NoSelector), 3, "token", 3},
+		{NewDataSelectQuery(chunked, NewSortQuery([]string{"a", "name"}), NoFilter, NoSelector), 0, "", 1},
+		{NewDataSelectQuery(chunked, NoSort, NewFilterQuery([]string{"name", "a"}), NoSelector), 0, "", 0},
+		{NewDataSelectQuery(NewChunkedPaginationQuery(-1, -1, ""), NoSort, NoFilter, NoSelector), 0, "", 3},
+		{NewDataSelectQuery(NewPaginationQuery(2, 0), NoSort, NoFilter, NoSelector), 0, "", 2},
+	}
+
+	for _, c := range cases {
+		options := c.query.ListOptions()
+		if options.Limit != c.limit || options.Continue != c.continueToken {
+			t.Errorf("ListOptions(): Expected limit %d and continue %q but got %d and %q", c.limit, c.continueToken,
+				options.Limit, options.Continue)
+		}
+
+		// Chunked lists are returned as they are, unless apiserver has returned more items than requested. Others
+		// are paginated in memory.
+		cells := []DataCell{testCell{name: "c"}, testCell{name: "b"}, testCell{name: "d"}}
+		if selected, _ := GenericDataSelectWithFilter(cells, c.query); len(selected) != c.selected {
+			t.Errorf("GenericDataSelectWithFilter(): Expected %d items but got %d", c.selected, len(selected))
+		}
+	}
+}
+
+func TestDataSelectQuery_ListOptionsFor(t *testing.T) {
+	cases := []struct {
+		selector      *SelectorQuery
+		kind          string
+		fieldSelector string
+	}{
+		{NewKindSelectorQuery("", "status.phase=Running", nil), "pod", "status.phase=Running"},
+		{NewKindSelectorQuery("", "status.phase=Running", []string{"Pod"}), "pod", "status.phase=Running"},
+		{NewKindSelectorQuery("", "status.phase=Running", []string{"pod"}), "deployment", ""},
+		{NoSelector, "deployment", ""},
+	}
+
+	for _, c := range cases {
+		query := NewDataSelectQuery(NoPagination, NoSort, NoFilter, c.selector)
+		if options := query.ListOptionsFor(c.kind); options.FieldSelector != c.fieldSelector {
+			t.Errorf("ListOptionsFor(%s): Expected field selector %q but got %q", c.kind, c.fieldSelector,
+				options.FieldSelector)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package dataselect
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"strconv"
+	"time"
+)
+
+// Formats lists can be exported in.
+const (
+	ExportCSV       = "csv"
+	ExportJSONLines = "jsonl"
+	ExportYAML      = "yaml"
+)
+
+// Columns exported if the query does not set them. All data cells expose these properties.
+var DefaultExportColumns = []PropertyName{NameProperty, NamespaceProperty, CreationTimestampProperty}
+
+// ExportQuery holds format and columns of exported list. Data cells of the list are collected by the query
+// after they are filtered and sorted, so the export contains the same data as the list without pagination.
+type ExportQuery struct {
+	Format string
+	// Columns are names of properties of exported data cells, i.e. 'restarts' for pods. Columns of properties
+	// not exposed by the cells are empty.
+	Columns []PropertyName
+	// Data cells collected from the list.
+	cells []DataCell
+}
+
+// NewExportQuery creates query that exports lists in given format with given columns. Default columns are used
+// if no columns are given. Error is returned for unknown formats.
+func NewExportQuery(format string, columns []string) (*ExportQuery, error) {
+	switch format {
+	case ExportCSV, ExportJSONLines, ExportYAML:
+	default:
+		return nil, fmt.Errorf("Unknown export format %q, expected one of %s, %s or %s", format, ExportCSV,
+			ExportJSONLines, ExportYAML)
+	}
+
+	query := &ExportQuery{Format: format, Columns: make([]PropertyName, 0)}
+	for _, column := range columns {
+		if len(column) > 0 {
+			query.Columns = append(query.Columns, PropertyName(column))
+		}
+	}
+	if len(query.Columns) == 0 {
+		query.Columns = DefaultExportColumns
+	}
+
+	return query, nil
+}
+
+// ContentType returns MIME type of the export.
+func (self *ExportQuery) ContentType() string {
+	switch self.Format {
+	case ExportCSV:
+		return "text/csv"
+	case ExportJSONLines:
+		return "application/x-ndjson"
+	default:
+		return "application/yaml"
+	}
+}
+
+// collect keeps data cells selected by the query, replacing the ones collected before.
+func (self *ExportQuery) collect(cells []DataCell) {
+	self.cells = append([]DataCell{}, cells...)
+}
+
+// Write writes collected data cells to given writer, one row or document per cell.
+func (self *ExportQuery) Write(writer io.Writer) error {
+	switch self.Format {
+	case ExportCSV:
+		return self.writeCSV(writer)
+	case ExportJSONLines:
+		return self.writeJSONLines(writer)
+	default:
+		return self.writeYAML(writer)
+	}
+}
+
+func (self *ExportQuery) writeCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	header := make([]string, len(self.Columns))
+	for i, column := range self.Columns {
+		header[i] = string(column)
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	for _, cell := range self.cells {
+		row := make([]string, len(self.Columns))
+		for i, column := range self.Columns {
+			row[i] = FormatComparableValue(cell.GetProperty(column))
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// Writes a JSON object per line. Keys of objects are written in the order of columns, so they can not be
+// marshalled from a map.
+func (self *ExportQuery) writeJSONLines(writer io.Writer) error {
+	for _, cell := range self.cells {
+		line := bytes.NewBufferString("{")
+		for i, column := range self.Columns {
+			key, err := json.Marshal(column)
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(exportValue(cell.GetProperty(column)))
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				line.WriteString(",")
+			}
+			line.Write(key)
+			line.WriteString(":")
+			line.Write(value)
+		}
+		line.WriteString("}\n")
+
+		if _, err := line.WriteTo(writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Writes a YAML list item per cell, so the output is a single valid YAML list even though it is streamed.
+func (self *ExportQuery) writeYAML(writer io.Writer) error {
+	if len(self.cells) == 0 {
+		_, err := io.WriteString(writer, "[]\n")
+		return err
+	}
+
+	for _, cell := range self.cells {
+		item := make(yaml.MapSlice, len(self.Columns))
+		for i, column := range self.Columns {
+			item[i] = yaml.MapItem{Key: string(column), Value: exportValue(cell.GetProperty(column))}
+		}
+
+		data, err := yaml.Marshal([]yaml.MapSlice{item})
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns value of exported JSON and YAML documents. Numbers are exported as numbers and other values as
+// strings. Nil is returned for properties not exposed by the cell.
+func exportValue(value ComparableValue) interface{} {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case StdComparableInt:
+		return int(value)
+	}
+
+	return FormatComparableValue(value)
+}
+
+// FormatComparableValue returns value in the same format it is accepted in by filters, i.e. 10Gi for quantities.
+// Empty string is returned for nil value.
+func FormatComparableValue(value ComparableValue) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case StdComparableString:
+		return string(value)
+	case StdComparableInt:
+		return strconv.Itoa(int(value))
+	case StdComparableTime:
+		return time.Time(value).UTC().Format(time.RFC3339)
+	case StdComparableQuantity:
+		quantity := resource.Quantity(value)
+		return quantity.String()
+	case StdComparableDuration:
+		return time.Duration(value).String()
+	}
+
+	return fmt.Sprint(value)
+}
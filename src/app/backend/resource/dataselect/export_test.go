@@ -0,0 +1,49 @@
+package dataselect
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"testing"
+	"time"
+)
+
+func TestFormatComparableValue(t *testing.T) {
+	cases := []struct {
+		value    ComparableValue
+		expected string
+	}{
+		{nil, ""},
+		{StdComparableString("web"), "web"},
+		{StdComparableInt(3), "3"},
+		{StdComparableTime(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)), "2018-03-01T10:00:00Z"},
+		{StdComparableQuantity(resource.MustParse("10Gi")), "10Gi"},
+		{StdComparableDuration(90 * time.Minute), "1h30m0s"},
+	}
+
+	for _, c := range cases {
+		if actual := FormatComparableValue(c.value); actual != c.expected {
+			t.Errorf("FormatComparableValue(%#v): Expected %q but got %q", c.value, c.expected, actual)
+		}
+
+		// Formatted values are accepted by filters.
+		if c.value != nil {
+			if parsed, err := parseComparableValue(c.value, c.expected); err != nil || parsed.Compare(c.value) != 0 {
+				t.Errorf("parseComparableValue(%q): Expected %#v but got %#v, %v", c.expected, c.value, parsed, err)
+			}
+		}
+	}
+}
+
+func TestNewExportQuery(t *testing.T) {
+	query, err := NewExportQuery(ExportCSV, []string{""})
+	if err != nil {
+		t.Fatalf("NewExportQuery(): Unexpected error: %s", err)
+	}
+
+	if len(query.Columns) != len(DefaultExportColumns) {
+		t.Errorf("NewExportQuery(): Expected default columns but got %v", query.Columns)
+	}
+
+	if _, err := NewExportQuery("xlsx", nil); err == nil {
+		t.Error("NewExportQuery(): Expected error for unknown format")
+	}
+}
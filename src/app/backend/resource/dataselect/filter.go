@@ -2,6 +2,8 @@ package dataselect
 
 type FilterQuery struct {
 	FilterByList []FilterBy
+	// Expression data has to match in addition to FilterByList. Nil if the data is not filtered by expression.
+	Expression FilterExpression
 }
 
 type FilterBy struct {
@@ -36,3 +38,17 @@ func NewFilterQuery(filterByListRaw []string) *FilterQuery {
 	}
 }
 
+// NewExpressionFilterQuery takes raw filter options list, in the same form as NewFilterQuery, and filter
+// expression, and returns FilterQuery that filters data by both of them. FilterError is returned if the
+// expression is invalid.
+func NewExpressionFilterQuery(filterByListRaw []string, expression string) (*FilterQuery, error) {
+	filterExpression, err := ParseFilterExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FilterQuery{
+		FilterByList: NewFilterQuery(filterByListRaw).FilterByList,
+		Expression:   filterExpression,
+	}, nil
+}
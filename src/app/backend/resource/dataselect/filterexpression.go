@@ -0,0 +1,476 @@
+package dataselect
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterExpression is a parsed filter expression, i.e. 'status!=Running && restarts>3 || name~^api-'. Supported
+// operators, from the lowest precedence, are:
+//
+//	a || b, a && b, !a, (a)
+//	prop==value, prop=value, prop!=value, prop>value, prop>=value, prop<value, prop<=value
+//	prop~regexp, prop!~regexp
+//	prop in (value1, value2), prop notin (value1, value2)
+//
+// Values are compared as the type of the property, i.e. as numbers for numeric properties, as quantities like
+// 10Gi for capacities and as durations like 1h30m for ages. Values containing spaces or operator characters have
+// to be quoted, i.e. name~"^(api|web)-".
+type FilterExpression interface {
+	// Matches returns true if the data cell matches the expression. Cells without the property used in the
+	// comparison do not match it.
+	Matches(cell DataCell) bool
+}
+
+// FilterErrorReason describes why the filter expression is invalid.
+type FilterErrorReason string
+
+// Reasons of invalid filter expressions.
+const (
+	// FilterErrorSyntax means that the expression contains unexpected token or it ends unexpectedly.
+	FilterErrorSyntax FilterErrorReason = "Syntax"
+	// FilterErrorOperator means that the comparison uses unknown operator.
+	FilterErrorOperator FilterErrorReason = "Operator"
+	// FilterErrorValue means that the value can not be used with the operator, i.e. invalid regexp.
+	FilterErrorValue FilterErrorReason = "Value"
+)
+
+// FilterError is returned for invalid filter expressions.
+type FilterError struct {
+	Reason     FilterErrorReason
+	Expression string
+	// Position of the invalid token in the expression.
+	Position int
+	Message  string
+}
+
+// Error implements error.
+func (self *FilterError) Error() string {
+	return fmt.Sprintf("Invalid filter expression %q at position %d: %s", self.Expression, self.Position,
+		self.Message)
+}
+
+// ParseFilterExpression parses filter expression. Nil expression is returned for empty string.
+func ParseFilterExpression(expression string) (FilterExpression, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, nil
+	}
+
+	tokens, err := tokenizeFilterExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &filterParser{expression: expression, tokens: tokens}
+	result, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := parser.peek(); token.kind != tokenEnd {
+		return nil, parser.error(FilterErrorSyntax, token, fmt.Sprintf("unexpected %q", token.text))
+	}
+
+	return result, nil
+}
+
+type andExpression struct {
+	left  FilterExpression
+	right FilterExpression
+}
+
+// Matches implements FilterExpression.
+func (self andExpression) Matches(cell DataCell) bool {
+	return self.left.Matches(cell) && self.right.Matches(cell)
+}
+
+type orExpression struct {
+	left  FilterExpression
+	right FilterExpression
+}
+
+// Matches implements FilterExpression.
+func (self orExpression) Matches(cell DataCell) bool {
+	return self.left.Matches(cell) || self.right.Matches(cell)
+}
+
+type notExpression struct {
+	expression FilterExpression
+}
+
+// Matches implements FilterExpression.
+func (self notExpression) Matches(cell DataCell) bool {
+	return !self.expression.Matches(cell)
+}
+
+// Comparison of a single property with one or more values.
+type comparisonExpression struct {
+	property PropertyName
+	operator string
+	values   []string
+	// Compiled value of regexp operators.
+	regexp *regexp.Regexp
+}
+
+// Matches implements FilterExpression.
+func (self comparisonExpression) Matches(cell DataCell) bool {
+	value := cell.GetProperty(self.property)
+	if value == nil {
+		return false
+	}
+
+	switch self.operator {
+	case "~", "!~":
+		str, ok := value.(StdComparableString)
+		return ok && self.regexp.MatchString(string(str)) == (self.operator == "~")
+	case "in", "notin":
+		return self.in(value) == (self.operator == "in")
+	}
+
+	cmp, ok := compareWithRaw(value, self.values[0])
+	if !ok {
+		return false
+	}
+
+	switch self.operator {
+	case "==", "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+
+	return false
+}
+
+func (self comparisonExpression) in(value ComparableValue) bool {
+	for _, raw := range self.values {
+		if cmp, ok := compareWithRaw(value, raw); ok && cmp == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Compares the value with raw value parsed as the same type. False is returned if raw value can not be parsed.
+func compareWithRaw(value ComparableValue, raw string) (int, bool) {
+	other, err := parseComparableValue(value, raw)
+	if err != nil {
+		return 0, false
+	}
+
+	return value.Compare(other), true
+}
+
+// Parses raw value from filter expression as the same type as given value.
+func parseComparableValue(like ComparableValue, raw string) (ComparableValue, error) {
+	switch like.(type) {
+	case StdComparableString:
+		return StdComparableString(raw), nil
+	case StdComparableInt:
+		value, err := strconv.Atoi(raw)
+		return StdComparableInt(value), err
+	case StdComparableTime:
+		value, err := time.Parse(time.RFC3339, raw)
+		return StdComparableTime(value), err
+	case StdComparableQuantity:
+		value, err := resource.ParseQuantity(raw)
+		return StdComparableQuantity(value), err
+	case StdComparableDuration:
+		value, err := time.ParseDuration(raw)
+		return StdComparableDuration(value), err
+	}
+
+	return nil, fmt.Errorf("Values of type %T can not be compared", like)
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	// Unquoted identifier or value.
+	tokenWord
+	// Quoted value.
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// Operators of filter expressions. Longer operators are listed first, so they are not split by the tokenizer.
+var filterOperators = []string{"&&", "||", "==", "!=", ">=", "<=", "!~", "=", ">", "<", "~", "!", "(", ")", ","}
+
+// Operators that compare property with a single value.
+var comparisonOperators = []string{"==", "=", "!=", ">", ">=", "<", "<=", "~", "!~"}
+
+// Characters that end unquoted words.
+const filterSpecialCharacters = "&|=!<>~(),\""
+
+func tokenizeFilterExpression(expression string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		if c == ' ' || c == '\t' || c == '\n' {
+			i++
+			continue
+		}
+
+		if c == '"' {
+			end := i + 1
+			for ; end < len(expression) && expression[end] != '"'; end++ {
+				if expression[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(expression) {
+				return nil, &FilterError{Reason: FilterErrorSyntax, Expression: expression, Position: i,
+					Message: "unterminated quoted value"}
+			}
+
+			value, err := strconv.Unquote(expression[i : end+1])
+			if err != nil {
+				return nil, &FilterError{Reason: FilterErrorSyntax, Expression: expression, Position: i,
+					Message: fmt.Sprintf("invalid quoted value: %s", err)}
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: value, position: i})
+			i = end + 1
+			continue
+		}
+
+		if operator := matchOperator(expression[i:]); len(operator) > 0 {
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: i})
+			i += len(operator)
+			continue
+		}
+
+		// Single '&' or '|' are not operators and can not be part of unquoted words.
+		if strings.IndexByte(filterSpecialCharacters, c) >= 0 {
+			return nil, &FilterError{Reason: FilterErrorOperator, Expression: expression, Position: i,
+				Message: fmt.Sprintf("unknown operator %q", c)}
+		}
+
+		end := i
+		for ; end < len(expression); end++ {
+			if strings.IndexByte(" \t\n"+filterSpecialCharacters, expression[end]) >= 0 {
+				break
+			}
+		}
+
+		tokens = append(tokens, token{kind: tokenWord, text: expression[i:end], position: i})
+		i = end
+	}
+
+	return append(tokens, token{kind: tokenEnd, position: len(expression)}), nil
+}
+
+func matchOperator(expression string) string {
+	for _, operator := range filterOperators {
+		if strings.HasPrefix(expression, operator) {
+			return operator
+		}
+	}
+
+	return ""
+}
+
+// Recursive descent parser of tokenized filter expression.
+type filterParser struct {
+	expression string
+	tokens     []token
+	current    int
+}
+
+func (self *filterParser) peek() token {
+	return self.tokens[self.current]
+}
+
+func (self *filterParser) next() token {
+	token := self.tokens[self.current]
+	if token.kind != tokenEnd {
+		self.current++
+	}
+
+	return token
+}
+
+func (self *filterParser) isOperator(operator string) bool {
+	token := self.peek()
+	return token.kind == tokenOperator && token.text == operator
+}
+
+func (self *filterParser) expect(operator string) error {
+	if token := self.next(); token.kind != tokenOperator || token.text != operator {
+		return self.unexpected(token, fmt.Sprintf("%q", operator))
+	}
+
+	return nil
+}
+
+func (self *filterParser) parseOr() (FilterExpression, error) {
+	left, err := self.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for self.isOperator("||") {
+		self.next()
+		right, err := self.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (self *filterParser) parseAnd() (FilterExpression, error) {
+	left, err := self.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for self.isOperator("&&") {
+		self.next()
+		right, err := self.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (self *filterParser) parseUnary() (FilterExpression, error) {
+	if self.isOperator("!") {
+		self.next()
+		expression, err := self.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{expression: expression}, nil
+	}
+
+	if self.isOperator("(") {
+		self.next()
+		expression, err := self.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return expression, self.expect(")")
+	}
+
+	return self.parseComparison()
+}
+
+func (self *filterParser) parseComparison() (FilterExpression, error) {
+	property := self.next()
+	if property.kind != tokenWord {
+		return nil, self.unexpected(property, "property name")
+	}
+
+	operator := self.next()
+	if operator.kind == tokenWord && (operator.text == "in" || operator.text == "notin") {
+		values, err := self.parseValueList()
+		if err != nil {
+			return nil, err
+		}
+		return comparisonExpression{property: PropertyName(property.text), operator: operator.text,
+			values: values}, nil
+	}
+
+	if operator.kind == tokenEnd {
+		return nil, self.unexpected(operator, "operator")
+	}
+
+	if operator.kind != tokenOperator || !isComparisonOperator(operator.text) {
+		return nil, self.error(FilterErrorOperator, operator, fmt.Sprintf("unknown operator %q", operator.text))
+	}
+
+	value, err := self.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	result := comparisonExpression{property: PropertyName(property.text), operator: operator.text,
+		values: []string{value.text}}
+	if operator.text == "~" || operator.text == "!~" {
+		if result.regexp, err = regexp.Compile(value.text); err != nil {
+			return nil, self.error(FilterErrorValue, value, fmt.Sprintf("invalid regexp: %s", err))
+		}
+	}
+
+	return result, nil
+}
+
+// Parses list of values of set operators, i.e. '(value1, value2)'.
+func (self *filterParser) parseValueList() ([]string, error) {
+	if err := self.expect("("); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0)
+	for {
+		value, err := self.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value.text)
+
+		if !self.isOperator(",") {
+			break
+		}
+		self.next()
+	}
+
+	return values, self.expect(")")
+}
+
+func (self *filterParser) parseValue() (token, error) {
+	value := self.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return value, self.unexpected(value, "value")
+	}
+
+	return value, nil
+}
+
+func (self *filterParser) unexpected(token token, expected string) error {
+	if token.kind == tokenEnd {
+		return self.error(FilterErrorSyntax, token, fmt.Sprintf("expected %s but expression ended", expected))
+	}
+
+	return self.error(FilterErrorSyntax, token, fmt.Sprintf("expected %s but got %q", expected, token.text))
+}
+
+func (self *filterParser) error(reason FilterErrorReason, token token, message string) error {
+	return &FilterError{Reason: reason, Expression: self.expression, Position: token.position, Message: message}
+}
+
+func isComparisonOperator(operator string) bool {
+	for _, each := range comparisonOperators {
+		if each == operator {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package dataselect
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testCell struct {
+	name     string
+	status   string
+	restarts int
+}
+
+func (self testCell) GetProperty(name PropertyName) ComparableValue {
+	switch name {
+	case NameProperty:
+		return StdComparableString(self.name)
+	case StatusProperty:
+		return StdComparableString(self.status)
+	case "restarts":
+		return StdComparableInt(self.restarts)
+	default:
+		return nil
+	}
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	cells := []testCell{
+		{name: "api-server", status: "Running", restarts: 0},
+		{name: "api-worker", status: "Failed", restarts: 1},
+		{name: "web", status: "Pending", restarts: 12},
+		{name: "db", status: "Running", restarts: 4},
+	}
+
+	cases := []struct {
+		expression string
+		expected   []string
+	}{
+		{"status!=Running && restarts>3 || name~^api-", []string{"api-server", "api-worker", "web"}},
+		{"status==Running && (restarts>=4 || name=web)", []string{"db"}},
+		{"!(status in (Running, Pending))", []string{"api-worker"}},
+		{"status notin (Running) && restarts<=1", []string{"api-worker"}},
+		{`name!~"^(api|web)"`, []string{"db"}},
+		{"restarts>abc || unknown==1", []string{}},
+	}
+
+	for _, c := range cases {
+		expression, err := ParseFilterExpression(c.expression)
+		if err != nil {
+			t.Fatalf("ParseFilterExpression(%s): Unexpected error: %s", c.expression, err)
+		}
+
+		matched := []string{}
+		for _, cell := range cells {
+			if expression.Matches(cell) {
+				matched = append(matched, cell.name)
+			}
+		}
+
+		if !reflect.DeepEqual(matched, c.expected) {
+			t.Errorf("ParseFilterExpression(%s): Expected %v to match but got %v", c.expression, c.expected,
+				matched)
+		}
+	}
+}
+
+func TestParseFilterExpression_Errors(t *testing.T) {
+	cases := []struct {
+		expression string
+		reason     FilterErrorReason
+		position   int
+	}{
+		{"status==Running &&", FilterErrorSyntax, 18},
+		{"(status==Running", FilterErrorSyntax, 16},
+		{"status Running", FilterErrorOperator, 7},
+		{"status==Running & name==web", FilterErrorOperator, 16},
+		{`name=="api`, FilterErrorSyntax, 6},
+		{"name~(", FilterErrorSyntax, 5},
+		{`name~"("`, FilterErrorValue, 5},
+		{"status in Running", FilterErrorSyntax, 10},
+	}
+
+	for _, c := range cases {
+		_, err := ParseFilterExpression(c.expression)
+		filterErr, ok := err.(*FilterError)
+		if !ok {
+			t.Errorf("ParseFilterExpression(%s): Expected FilterError but got %v", c.expression, err)
+			continue
+		}
+
+		if filterErr.Reason != c.reason || filterErr.Position != c.position {
+			t.Errorf("ParseFilterExpression(%s): Expected %s error at %d but got %s", c.expression, c.reason,
+				c.position, filterErr)
+		}
+	}
+}
+
+func TestNewExpressionFilterQuery(t *testing.T) {
+	query, err := NewExpressionFilterQuery([]string{"name", "api"}, "status==Running")
+	if err != nil {
+		t.Fatalf("NewExpressionFilterQuery(): Unexpected error: %s", err)
+	}
+
+	cells := []DataCell{
+		testCell{name: "api-server", status: "Running"},
+		testCell{name: "api-worker", status: "Failed"},
+		testCell{name: "web", status: "Running"},
+	}
+	selected, total := GenericDataSelectWithFilter(cells,
+		NewDataSelectQuery(NoPagination, NoSort, query, NoSelector))
+	if total != 1 || selected[0].(testCell).name != "api-server" {
+		t.Errorf("NewExpressionFilterQuery(): Expected only api-server to match both filters but got %v", selected)
+	}
+}
@@ -15,10 +15,22 @@ type PaginationQuery struct {
 	ItemsPerPage	int
 	// Number of page that should be returned
 	Page int
+	// Chunked is true if pages should be requested from apiserver, so the full list is not downloaded for every
+	// page. The next page is selected by Continue token instead of Page, which is used only if the list has to be
+	// paginated in memory.
+	Chunked bool
+	// Continue is the token of the page returned by apiserver with previous page. Empty for the first page.
+	Continue string
 }
 
 func NewPaginationQuery(itemsPerPage, page int) *PaginationQuery {
-	return &PaginationQuery{itemsPerPage, page}
+	return &PaginationQuery{ItemsPerPage: itemsPerPage, Page: page}
+}
+
+// NewChunkedPaginationQuery creates query that requests pages of itemsPerPage items from apiserver. Continue
+// token is empty for the first page. Given page is returned if the list has to be paginated in memory.
+func NewChunkedPaginationQuery(itemsPerPage, page int, continueToken string) *PaginationQuery {
+	return &PaginationQuery{ItemsPerPage: itemsPerPage, Page: page, Chunked: true, Continue: continueToken}
 }
 
 func (p *PaginationQuery) IsValidPagination() bool {
@@ -8,4 +8,30 @@ const (
 	CreationTimestampProperty 	= "creationTimestamp"
 	NamespaceProperty 					= "namespace"
 	StatusProperty 							= "status"
+	AgeProperty									= "age"
+	TypeProperty								= "type"
+
+	// Pod properties.
+	RestartsProperty						= "restarts"
+	NodeProperty								= "node"
+	PhaseProperty								= "phase"
+	QOSClassProperty						= "qosClass"
+	ImageProperty								= "image"
+
+	// Workload properties.
+	ReplicasProperty						= "replicas"
+	ReadyReplicasProperty				= "readyReplicas"
+	AvailableReplicasProperty		= "availableReplicas"
+
+	// Service properties.
+	ClusterIPProperty						= "clusterIP"
+
+	// Persistent volume claim properties.
+	CapacityProperty						= "capacity"
+	StorageClassProperty				= "storageClass"
+
+	// Node properties.
+	ReadyProperty								= "ready"
+	AllocatableCPUProperty			= "allocatableCPU"
+	AllocatableMemoryProperty		= "allocatableMemory"
 )
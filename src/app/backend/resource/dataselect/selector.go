@@ -0,0 +1,71 @@
+package dataselect
+
+import (
+	"github.com/wzt3309/k8sconsole/src/app/backend/api"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+// SelectorQuery holds label and field selectors that are passed to apiserver, so lists are filtered before
+// they are returned, i.e. 'app=payments,tier!=canary'.
+type SelectorQuery struct {
+	LabelSelector string
+	FieldSelector string
+	// Kinds the selectors are applied to in views that list several kinds, i.e. 'deployment'. Field selectors
+	// are specific to kinds, so they usually can not be applied to all of them. Selectors are applied to all
+	// listed kinds if it is empty.
+	Kinds []string
+}
+
+// NoSelector selects all objects.
+var NoSelector = NewSelectorQuery("", "")
+
+// NewSelectorQuery creates query from label and field selectors in the format accepted by apiserver.
+func NewSelectorQuery(labelSelector, fieldSelector string) *SelectorQuery {
+	return &SelectorQuery{LabelSelector: labelSelector, FieldSelector: fieldSelector}
+}
+
+// NewKindSelectorQuery creates query whose selectors are applied only to given kinds in views that list several
+// kinds.
+func NewKindSelectorQuery(labelSelector, fieldSelector string, kinds []string) *SelectorQuery {
+	query := NewSelectorQuery(labelSelector, fieldSelector)
+	for _, kind := range kinds {
+		if len(kind) > 0 {
+			query.Kinds = append(query.Kinds, strings.ToLower(kind))
+		}
+	}
+
+	return query
+}
+
+// AppliesTo returns true if selectors of the query are applied to objects of given kind.
+func (self *SelectorQuery) AppliesTo(kind string) bool {
+	if self == nil || len(self.Kinds) == 0 {
+		return true
+	}
+
+	for _, each := range self.Kinds {
+		if each == strings.ToLower(kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToListOptions returns list options that select objects matching the query.
+func (self *SelectorQuery) ToListOptions() metaV1.ListOptions {
+	options := api.ListEverything
+	if self == nil {
+		return options
+	}
+
+	if len(self.LabelSelector) > 0 {
+		options.LabelSelector = self.LabelSelector
+	}
+	if len(self.FieldSelector) > 0 {
+		options.FieldSelector = self.FieldSelector
+	}
+
+	return options
+}
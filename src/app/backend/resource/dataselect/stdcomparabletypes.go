@@ -1,6 +1,7 @@
 package dataselect
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	"strings"
 	"time"
 )
@@ -39,6 +40,31 @@ func (self StdComparableTime) Contains(otherV ComparableValue) bool {
 	return self.Compare(otherV) == 0
 }
 
+// StdComparableQuantity compares resource quantities, i.e. CPU or storage capacity, by their value, so 1Gi is
+// greater than 900Mi.
+type StdComparableQuantity resource.Quantity
+
+func (self StdComparableQuantity) Compare(otherV ComparableValue) int {
+	other := otherV.(StdComparableQuantity)
+	quantity := resource.Quantity(self)
+	return quantity.Cmp(resource.Quantity(other))
+}
+
+func (self StdComparableQuantity) Contains(otherV ComparableValue) bool {
+	return self.Compare(otherV) == 0
+}
+
+type StdComparableDuration time.Duration
+
+func (self StdComparableDuration) Compare(otherV ComparableValue) int {
+	other := otherV.(StdComparableDuration)
+	return ints64Compare(int64(self), int64(other))
+}
+
+func (self StdComparableDuration) Contains(otherV ComparableValue) bool {
+	return self.Compare(otherV) == 0
+}
+
 func intsCompare(a, b int) int {
 	if a > b {
 		return 1
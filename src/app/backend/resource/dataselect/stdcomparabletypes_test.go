@@ -0,0 +1,68 @@
+package dataselect
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testStorageCell struct {
+	name     string
+	capacity string
+	age      time.Duration
+}
+
+func (self testStorageCell) GetProperty(name PropertyName) ComparableValue {
+	switch name {
+	case NameProperty:
+		return StdComparableString(self.name)
+	case CapacityProperty:
+		return StdComparableQuantity(resource.MustParse(self.capacity))
+	case AgeProperty:
+		return StdComparableDuration(self.age)
+	default:
+		return nil
+	}
+}
+
+func TestStdComparableQuantity(t *testing.T) {
+	cells := []DataCell{
+		testStorageCell{name: "small", capacity: "900Mi", age: time.Hour},
+		testStorageCell{name: "large", capacity: "1Ti", age: time.Minute},
+		testStorageCell{name: "medium", capacity: "1Gi", age: 48 * time.Hour},
+	}
+
+	cases := []struct {
+		sortQuery   *SortQuery
+		filterQuery *FilterQuery
+		expected    []string
+	}{
+		{NewSortQuery([]string{"d", "capacity"}), NoFilter, []string{"large", "medium", "small"}},
+		{NewSortQuery([]string{"a", "age"}), NoFilter, []string{"large", "small", "medium"}},
+		{NoSort, NewFilterQuery([]string{"capacity", "1024Mi"}), []string{"medium"}},
+		{NoSort, mustFilterQuery(t, "capacity>=1Gi && age<1h30m"), []string{"large"}},
+	}
+
+	for _, c := range cases {
+		selected, _ := GenericDataSelectWithFilter(append([]DataCell{}, cells...),
+			NewDataSelectQuery(NoPagination, c.sortQuery, c.filterQuery, NoSelector))
+		names := []string{}
+		for _, cell := range selected {
+			names = append(names, cell.(testStorageCell).name)
+		}
+
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("GenericDataSelectWithFilter(): Expected %v but got %v", c.expected, names)
+		}
+	}
+}
+
+func mustFilterQuery(t *testing.T, expression string) *FilterQuery {
+	query, err := NewExpressionFilterQuery(nil, expression)
+	if err != nil {
+		t.Fatalf("NewExpressionFilterQuery(%s): Unexpected error: %s", expression, err)
+	}
+
+	return query
+}
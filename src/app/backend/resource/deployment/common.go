@@ -18,6 +18,15 @@ func (self DeploymentCell) GetProperty(name dataselect.PropertyName) dataselect.
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.ReplicasProperty:
+		if self.Spec.Replicas == nil {
+			return nil
+		}
+		return dataselect.StdComparableInt(*self.Spec.Replicas)
+	case dataselect.ReadyReplicasProperty:
+		return dataselect.StdComparableInt(self.Status.ReadyReplicas)
+	case dataselect.AvailableReplicasProperty:
+		return dataselect.StdComparableInt(self.Status.AvailableReplicas)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -3,12 +3,14 @@ package deployment
 import (
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/wzt3309/k8sconsole/src/app/backend/dryrun"
 	"io"
 	apps "k8s.io/api/apps/v1beta2"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -86,6 +88,9 @@ type AppDeploymentFromFileSpec struct {
 
 	// Whether validate content before creation or not
 	Validate bool `json:"validate"`
+
+	// Whether objects should be only sent to apiserver with dry-run to preview them
+	DryRun bool `json:"dryRun"`
 }
 
 // AppDeploymentFromFileResponse is a specification for deployment from file
@@ -98,6 +103,9 @@ type AppDeploymentFromFileResponse struct {
 
 	// Error after create resource
 	Error string `json:"error"`
+
+	// Objects as they would be created, set only in dry-run mode
+	DryRunResults []dryrun.Result `json:"dryRunResults,omitempty"`
 }
 
 type PortMapping struct {
@@ -244,17 +252,109 @@ func GetAvailableProtocols() *Protocols {
 	return &Protocols{Protocols: []v1.Protocol{v1.ProtocolTCP, v1.ProtocolUDP}}
 }
 
-func DeployAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec) (bool, error) {
-	reader := strings.NewReader(spec.Content)
+// CreatedObjectHandler is called with every object created from the file and its resource.
+type CreatedObjectHandler func(resource *metaV1.APIResource, object runtime.Object)
+
+// DeployAppFromFile creates objects from the file. Given handler is called with every created object, also when
+// creation of later objects fails.
+func DeployAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec, onCreated CreatedObjectHandler) (bool,
+	error) {
 	glog.Infof("Deploy %s file in %s namespace", spec.Name, spec.Namespace)
+	err := forEachFileObject(cfg, spec, func(data *unstructured.Unstructured, resource *metaV1.APIResource,
+		gvk schema.GroupVersionKind, namespace string) error {
+		dynamicClientPool := dynamic.NewDynamicClientPool(cfg)
+
+		dynamicClient, err := dynamicClientPool.ClientForGroupVersionKind(gvk)
+
+		if err != nil {
+			return err
+		}
+
+		created, err := dynamicClient.Resource(resource, namespace).Create(data)
+		if err != nil {
+			return err
+		}
+
+		if onCreated != nil {
+			onCreated(resource, created)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DryRunAppFromFile creates objects from the file with dry-run and returns objects as they would be created.
+// Objects are compared with no live object, so every field is reported as added.
+func DryRunAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec) ([]dryrun.Result, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dryrun.CheckSupported(discoveryClient); err != nil {
+		return nil, err
+	}
+
+	results := make([]dryrun.Result, 0)
+	err = forEachFileObject(cfg, spec, func(data *unstructured.Unstructured, resource *metaV1.APIResource,
+		gvk schema.GroupVersionKind, namespace string) error {
+		body, err := data.MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		apiPath := "/apis/" + gvk.GroupVersion().String()
+		if len(gvk.Group) == 0 {
+			apiPath = "/api/" + gvk.Version
+		}
+
+		req := discoveryClient.RESTClient().Post().
+			AbsPath(apiPath).
+			Resource(resource.Name).
+			Param(dryrun.DryRunParam, dryrun.DryRunAll).
+			SetHeader("Content-Type", "application/json").
+			SetHeader("Accept", "application/json").
+			Body(body)
+
+		if resource.Namespaced {
+			req.Namespace(namespace)
+		}
+
+		raw, err := req.Do().Raw()
+		if err != nil {
+			return err
+		}
+
+		result, err := dryrun.NewResult(nil, raw)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, *result)
+		return nil
+	})
+
+	return results, err
+}
+
+// Decodes objects from the file content and calls given function with every object, its resource and namespace
+// it should be created in.
+func forEachFileObject(cfg *rest.Config, spec *AppDeploymentFromFileSpec, fn func(data *unstructured.Unstructured,
+	resource *metaV1.APIResource, gvk schema.GroupVersionKind, namespace string) error) error {
+	reader := strings.NewReader(spec.Content)
 	d := yaml.NewYAMLOrJSONDecoder(reader, 4096)
 	for {
 		data := unstructured.Unstructured{}
 		if err := d.Decode(&data); err != nil {
 			if err == io.EOF {
-				return true, nil
+				return nil
 			}
-			return false, err
+			return err
 		}
 
 		version := data.GetAPIVersion()
@@ -269,12 +369,12 @@ func DeployAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec) (bool,
 
 		discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
 		if err != nil {
-			return false, err
+			return err
 		}
 
 		apiResourceList, err := discoveryClient.ServerResourcesForGroupVersion(version)
 		if err != nil {
-			return false, err
+			return err
 		}
 		apiResources := apiResourceList.APIResources
 		var resource *metaV1.APIResource
@@ -285,29 +385,18 @@ func DeployAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec) (bool,
 			}
 		}
 		if resource == nil {
-			return false, fmt.Errorf("Unknown resource kind: %s", kind)
-		}
-
-		dynamicClientPool := dynamic.NewDynamicClientPool(cfg)
-
-		dynamicClient, err := dynamicClientPool.ClientForGroupVersionKind(groupVersionKind)
-
-		if err != nil {
-			return false, err
+			return fmt.Errorf("Unknown resource kind: %s", kind)
 		}
 
+		namespace := spec.Namespace
 		if strings.Compare(spec.Namespace, "_all") == 0 {
-			_, err = dynamicClient.Resource(resource, data.GetNamespace()).Create(&data)
-		} else {
-			_, err = dynamicClient.Resource(resource, spec.Namespace).Create(&data)
+			namespace = data.GetNamespace()
 		}
 
-		if err != nil {
-			return false, err
+		if err := fn(&data, resource, groupVersionKind, namespace); err != nil {
+			return err
 		}
 	}
-
-	return true, nil
 }
 
 func generatePortMappingName(portMapping PortMapping) string {
@@ -46,7 +46,7 @@ func GetDeploymentList(client kubernetes.Interface, nsQuery *common.NamespaceQue
 	glog.Info("Getting list of deployments in the cluster")
 
 	channels := &common.ResourceChannels{
-		DeploymentList: common.GetDeploymentListChannel(client, nsQuery, 1),
+		DeploymentList: common.GetDeploymentListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		PodList: common.GetPodListChannel(client, nsQuery, 1),
 		EventList: common.GetEventListChannel(client, nsQuery, 1),
 		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
@@ -86,6 +86,8 @@ func GetDeploymentListFromChannels(channels *common.ResourceChannels, dsQuery *d
 	}
 
 	deploymentList := toDeploymentList(deployments.Items, pods.Items, events.Items, rs.Items, nonCriticalErrors, dsQuery)
+
+	deploymentList.ListMeta.Continue = deployments.Continue
 	return deploymentList, nil
 }
 
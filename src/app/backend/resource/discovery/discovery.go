@@ -23,8 +23,8 @@ func GetDiscovery(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 
 		glog.Info("Getting discovery and load balancing category")
 		channels := &common.ResourceChannels{
-			ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
-			IngressList: common.GetIngressListChannel(client, nsQuery, 1),
+			ServiceList: common.GetServiceListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("service"), 1),
+			IngressList: common.GetIngressListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("ingress"), 1),
 		}
 
 		return GetDiscoveryFromChannels(channels, dsQuery)
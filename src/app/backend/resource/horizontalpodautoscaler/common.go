@@ -1,8 +1,43 @@
 package horizontalpodautoscaler
 
+import (
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/dataselect"
+	autoscaling "k8s.io/api/autoscaling/v1"
+)
+
 // Simple mapping of an autoscaling.CrossVersionObjectReference
 type ScaleTargetRef struct {
 	Kind string `json:"kind"`
 	Name string `json:"name"`
 }
 
+type HorizontalPodAutoscalerCell autoscaling.HorizontalPodAutoscaler
+
+func (self HorizontalPodAutoscalerCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	default:
+		return nil
+	}
+}
+
+func toCells(std []autoscaling.HorizontalPodAutoscaler) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = HorizontalPodAutoscalerCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []autoscaling.HorizontalPodAutoscaler {
+	std := make([]autoscaling.HorizontalPodAutoscaler, len(cells))
+	for i := range std {
+		std[i] = autoscaling.HorizontalPodAutoscaler(cells[i].(HorizontalPodAutoscalerCell))
+	}
+	return std
+}
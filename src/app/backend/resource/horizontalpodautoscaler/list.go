@@ -4,6 +4,7 @@ import (
 	"github.com/wzt3309/k8sconsole/src/app/backend/api"
 	"github.com/wzt3309/k8sconsole/src/app/backend/errors"
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/common"
+	"github.com/wzt3309/k8sconsole/src/app/backend/resource/dataselect"
 	autoscaling "k8s.io/api/autoscaling/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -29,8 +30,9 @@ type HorizontalPodAutoscaler struct {
 	TargetCPUUtilizationPercentage  *int32         `json:"targetCPUUtilizationPercentage"`
 }
 
-func GetHorizontalPodAutoscalerList(client kubernetes.Interface, nsQuery *common.NamespaceQuery) (*HorizontalPodAutoscalerList, error) {
-	channel := common.GetHorizontalPodAutoscalerListChannel(client, nsQuery, 1)
+func GetHorizontalPodAutoscalerList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*HorizontalPodAutoscalerList, error) {
+	channel := common.GetHorizontalPodAutoscalerListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1)
 	hpaList := <-channel.List
 	err := <-channel.Error
 
@@ -39,7 +41,7 @@ func GetHorizontalPodAutoscalerList(client kubernetes.Interface, nsQuery *common
 		return nil, criticalError
 	}
 
-	return toHorizontalPodAutoscalerList(hpaList.Items, nonCriticalErrors), nil
+	return toHorizontalPodAutoscalerList(hpaList.Items, nonCriticalErrors, dsQuery), nil
 }
 
 func GetHorizontalPodAutoscalerListForResource(client kubernetes.Interface, namespace, kind, name string) (*HorizontalPodAutoscalerList, error) {
@@ -60,16 +62,20 @@ func GetHorizontalPodAutoscalerListForResource(client kubernetes.Interface, name
 		}
 	}
 
-	return toHorizontalPodAutoscalerList(filteredHpaList, nonCriticalErrors), nil
+	return toHorizontalPodAutoscalerList(filteredHpaList, nonCriticalErrors, dataselect.NoDataSelect), nil
 }
 
-func toHorizontalPodAutoscalerList(hpas []autoscaling.HorizontalPodAutoscaler, nonCriticalErrors []error) *HorizontalPodAutoscalerList {
+func toHorizontalPodAutoscalerList(hpas []autoscaling.HorizontalPodAutoscaler, nonCriticalErrors []error,
+	dsQuery *dataselect.DataSelectQuery) *HorizontalPodAutoscalerList {
 	hpaList := &HorizontalPodAutoscalerList{
 		HorizontalPodAutoscalers: make([]HorizontalPodAutoscaler, 0),
-		ListMeta:                 api.ListMeta{TotalItems: len(hpas)},
 		Errors:                   nonCriticalErrors,
 	}
 
+	hpaCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(hpas), dsQuery)
+	hpas = fromCells(hpaCells)
+	hpaList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+
 	for _, hpa := range hpas {
 		horizontalPodAutoscaler := toHorizontalPodAutoScaler(&hpa)
 		hpaList.HorizontalPodAutoscalers = append(hpaList.HorizontalPodAutoscalers, horizontalPodAutoscaler)
@@ -30,14 +30,16 @@ type IngressList struct {
 // GetIngressList returns all ingresses in the given namespace.
 func GetIngressList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*IngressList, error) {
-	ingressList, err := client.ExtensionsV1beta1().Ingresses(nsQuery.ToRequestParam()).List(api.ListEverything)
+	ingressList, err := client.ExtensionsV1beta1().Ingresses(nsQuery.ToRequestParam()).List(dsQuery.ListOptions())
 
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil {
 		return nil, criticalError
 	}
 
-	return toIngressList(ingressList.Items, nonCriticalErrors, dsQuery), nil
+	result := toIngressList(ingressList.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = ingressList.Continue
+	return result, nil
 }
 
 // GetIngressListFromChannels - return all ingresses in the given namespace.
@@ -51,7 +53,9 @@ func GetIngressListFromChannels(channels *common.ResourceChannels, dsQuery *data
 		return nil, criticalError
 	}
 
-	return toIngressList(ingresses.Items, nonCriticalErrors, dsQuery), nil
+	result := toIngressList(ingresses.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = ingresses.Continue
+	return result, nil
 }
 
 func toIngressList(ingresses []extensions.Ingress, nonCriticalErrors []error,
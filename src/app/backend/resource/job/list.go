@@ -72,7 +72,7 @@ func GetJobList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 	glog.Info("Getting list of all jobs in the cluster")
 
 	channels := &common.ResourceChannels{
-		JobList:   common.GetJobListChannel(client, nsQuery, 1),
+		JobList:   common.GetJobListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		PodList:   common.GetPodListChannel(client, nsQuery, 1),
 		EventList: common.GetEventListChannel(client, nsQuery, 1),
 	}
@@ -105,6 +105,8 @@ func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *datasele
 	}
 
 	jobList := ToJobList(jobs.Items, pods.Items, events.Items, nonCriticalErrors, dsQuery)
+
+	jobList.ListMeta.Continue = jobs.Continue
 	jobList.Status = getStatus(jobs, pods.Items, events.Items)
 	return jobList, nil
 }
@@ -34,14 +34,16 @@ type Namespace struct {
 // GetNamespaceList returns a list of all namespaces in the cluster.
 func GetNamespaceList(client kubernetes.Interface, dsQuery *dataselect.DataSelectQuery) (*NamespaceList, error) {
 	log.Println("Getting list of namespaces")
-	namespaces, err := client.CoreV1().Namespaces().List(api.ListEverything)
+	namespaces, err := client.CoreV1().Namespaces().List(dsQuery.ListOptions())
 
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil {
 		return nil, criticalError
 	}
 
-	return toNamespaceList(namespaces.Items, nonCriticalErrors, dsQuery), nil
+	namespaceList := toNamespaceList(namespaces.Items, nonCriticalErrors, dsQuery)
+	namespaceList.ListMeta.Continue = namespaces.Continue
+	return namespaceList, nil
 }
 
 // GetNamespaceListFromChannels returns a list of all namespaces in the cluster.
@@ -54,7 +56,9 @@ func GetNamespaceListFromChannels(channels *common.ResourceChannels, dsQuery *da
 		return nil, criticalError
 	}
 
-	return toNamespaceList(namespaces.Items, nonCriticalErrors, dsQuery), nil
+	namespaceList := toNamespaceList(namespaces.Items, nonCriticalErrors, dsQuery)
+	namespaceList.ListMeta.Continue = namespaces.Continue
+	return namespaceList, nil
 }
 
 func toNamespaceList(namespaces []v1.Namespace, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *NamespaceList {
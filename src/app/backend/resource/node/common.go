@@ -27,11 +27,29 @@ func (self NodeCell) GetProperty(name dataselect.PropertyName) dataselect.Compar
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.ReadyProperty:
+		return dataselect.StdComparableString(getNodeReadyStatus(v1.Node(self)))
+	case dataselect.AllocatableCPUProperty:
+		return dataselect.StdComparableQuantity(self.Status.Allocatable[v1.ResourceCPU])
+	case dataselect.AllocatableMemoryProperty:
+		return dataselect.StdComparableQuantity(self.Status.Allocatable[v1.ResourceMemory])
 	default:
 		return nil
 	}
 }
 
+// getNodeReadyStatus returns status of the ready condition of given node, i.e. 'True', or 'Unknown' if the node
+// has not reported it.
+func getNodeReadyStatus(node v1.Node) v1.ConditionStatus {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status
+		}
+	}
+
+	return v1.ConditionUnknown
+}
+
 func toCells(std []v1.Node) []dataselect.DataCell {
 	cells := make([]dataselect.DataCell, len(std))
 	for i := range std {
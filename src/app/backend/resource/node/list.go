@@ -38,19 +38,23 @@ func GetNodeListFromChannels(client client.Interface, channels *common.ResourceC
 		return nil, criticalError
 	}
 
-	return toNodeList(client, nodes.Items, nonCriticalErrors, dsQuery), nil
+	nodeList := toNodeList(client, nodes.Items, nonCriticalErrors, dsQuery)
+	nodeList.ListMeta.Continue = nodes.Continue
+	return nodeList, nil
 }
 
 // GetNodeList returns a list of all Nodes in the cluster.
 func GetNodeList(client client.Interface, dsQuery *dataselect.DataSelectQuery) (*NodeList, error) {
-	nodes, err := client.CoreV1().Nodes().List(api.ListEverything)
+	nodes, err := client.CoreV1().Nodes().List(dsQuery.ListOptions())
 
 	nonCriticalErrors, criticalErrors := kcErrors.HandleError(err)
 	if criticalErrors != nil {
 		return nil, criticalErrors
 	}
 
-	return toNodeList(client, nodes.Items, nonCriticalErrors, dsQuery), nil
+	nodeList := toNodeList(client, nodes.Items, nonCriticalErrors, dsQuery)
+	nodeList.ListMeta.Continue = nodes.Continue
+	return nodeList, nil
 }
 
 // GetNodeList returns a list of all Nodes in the cluster.
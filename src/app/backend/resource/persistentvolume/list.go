@@ -37,7 +37,7 @@ func GetPersistentVolumeList(client kubernetes.Interface, dsQuery *dataselect.Da
 	*PersistentVolumeList, error) {
 	glog.Info("Getting list persistent volumes")
 	channels := &common.ResourceChannels{
-		PersistentVolumeList: common.GetPersistentVolumeListChannel(client, 1),
+		PersistentVolumeList: common.GetPersistentVolumeListChannelWithOptions(client, dsQuery.ListOptions(), 1),
 	}
 	return GetPersistentVolumeListFromChannels(channels, dsQuery)
 }
@@ -53,7 +53,9 @@ func GetPersistentVolumeListFromChannels(channels *common.ResourceChannels, dsQu
 	if criticalError != nil {
 		return nil, criticalError
 	}
-	return toPersistentVolumeList(persistentVolumes.Items, nonCriticalErrors, dsQuery), nil
+	result := toPersistentVolumeList(persistentVolumes.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = persistentVolumes.Continue
+	return result, nil
 }
 
 func toPersistentVolumeList(persistentVolumes []v1.PersistentVolume, nonCriticalErrors []error,
@@ -72,6 +72,23 @@ func (self PersistentVolumeClaimCell) GetProperty(name dataselect.PropertyName)
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	case dataselect.CapacityProperty:
+		// Capacity is known after the claim is bound, requested storage is used until then.
+		capacity, ok := self.Status.Capacity[v1.ResourceStorage]
+		if !ok {
+			capacity, ok = self.Spec.Resources.Requests[v1.ResourceStorage]
+		}
+		if !ok {
+			return nil
+		}
+		return dataselect.StdComparableQuantity(capacity)
+	case dataselect.StorageClassProperty:
+		if self.Spec.StorageClassName == nil {
+			return nil
+		}
+		return dataselect.StdComparableString(*self.Spec.StorageClassName)
 	default:
 		return nil
 	}
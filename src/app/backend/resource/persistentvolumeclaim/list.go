@@ -37,7 +37,7 @@ func GetPersistentVolumeClaimList(client kubernetes.Interface, nsQuery *common.N
 
 	glog.Info("Getting list persistent volumes claims")
 	channels := &common.ResourceChannels{
-		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client, nsQuery, 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 	}
 
 	return GetPersistentVolumeClaimListFromChannels(channels, nsQuery, dsQuery)
@@ -53,7 +53,9 @@ func GetPersistentVolumeClaimListFromChannels(channels *common.ResourceChannels,
 		return nil, criticalError
 	}
 
-	return toPersistentVolumeChaimList(persistentVolumeClaims.Items, nonCriticalErrors, dsQuery), nil
+	result := toPersistentVolumeChaimList(persistentVolumeClaims.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = persistentVolumeClaims.Continue
+	return result, nil
 }
 
 func toPersistentVolumeChaimList(persistentVolumeClaims []v1.PersistentVolumeClaim,
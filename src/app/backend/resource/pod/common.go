@@ -5,6 +5,8 @@ import (
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/dataselect"
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/event"
 	"k8s.io/api/core/v1"
+	"strings"
+	"time"
 )
 
 // Gets restart count of given pod (total number of its containers restarts).
@@ -96,6 +98,19 @@ func (self PodCell) GetProperty(name dataselect.PropertyName) dataselect.Compara
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
 	case dataselect.StatusProperty:
 		return dataselect.StdComparableString(self.Status.Phase)
+	case dataselect.AgeProperty:
+		return dataselect.StdComparableDuration(time.Since(self.ObjectMeta.CreationTimestamp.Time))
+	case dataselect.RestartsProperty:
+		return dataselect.StdComparableInt(getRestartCount(v1.Pod(self)))
+	case dataselect.NodeProperty:
+		return dataselect.StdComparableString(self.Spec.NodeName)
+	case dataselect.PhaseProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	case dataselect.QOSClassProperty:
+		return dataselect.StdComparableString(self.Status.QOSClass)
+	case dataselect.ImageProperty:
+		// Images of all containers, so the filter matches pods that run the image in any container.
+		return dataselect.StdComparableString(strings.Join(common.GetContainerImages(&self.Spec), ","))
 	default:
 		// if name is not supported then just return a constant dummy value
 		return nil
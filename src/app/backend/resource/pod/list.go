@@ -8,7 +8,6 @@ import (
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/dataselect"
 	"github.com/wzt3309/k8sconsole/src/app/backend/resource/event"
 	"k8s.io/api/core/v1"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClient "k8s.io/client-go/kubernetes"
 )
 
@@ -60,7 +59,7 @@ func GetPodList(client k8sClient.Interface, nsQuery *common.NamespaceQuery,
 	glog.Info("Getting list of all pods in cluster")
 
 	channels := &common.ResourceChannels{
-		PodList: common.GetPodListChannelWithOptions(client, nsQuery, metaV1.ListOptions{}, 1),
+		PodList: common.GetPodListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		EventList: common.GetEventListChannel(client, nsQuery, 1),
 	}
 
@@ -85,6 +84,8 @@ func GetPodListFromChannels(channels *common.ResourceChannels, dsQuery *datasele
 	}
 
 	podList := ToPodList(pods.Items, eventList.Items, nonCriticalErrors, dsQuery)
+
+	podList.ListMeta.Continue = pods.Continue
 	podList.Status = getStatus(pods, eventList.Items)
 	return &podList, nil
 }
@@ -36,8 +36,8 @@ func GetRbacRoleBindingList(client kubernetes.Interface, dsQuery *dataselect.Dat
 	*RbacRoleBindingList, error) {
 	glog.Info("Getting list rbac role bindings.")
 	channels := &common.ResourceChannels{
-		RoleBindingList: common.GetRoleBindingListChannel(client, 1),
-		ClusterRoleBindingList: common.GetClusterRoleBindingListChannel(client, 1),
+		RoleBindingList: common.GetRoleBindingListChannelWithOptions(client, dsQuery.ListOptions(), 1),
+		ClusterRoleBindingList: common.GetClusterRoleBindingListChannelWithOptions(client, dsQuery.ListOptions(), 1),
 	}
 
 	return GetRbacRoleBindingListFromChannels(channels, dsQuery)
@@ -30,8 +30,8 @@ type RbacRole struct {
 func GetRbacRoleList(client kubernetes.Interface, dsQuery *dataselect.DataSelectQuery) (*RbacRoleList, error) {
 	glog.Info("Getting list of RBAC roles.")
 	channels := &common.ResourceChannels{
-		RoleList: common.GetRoleListChannel(client, 1),
-		ClusterRoleList: common.GetClusterRoleListChannel(client, 1),
+		RoleList: common.GetRoleListChannelWithOptions(client, dsQuery.ListOptions(), 1),
+		ClusterRoleList: common.GetClusterRoleListChannelWithOptions(client, dsQuery.ListOptions(), 1),
 	}
 
 	return GetRbacRoleListFromChannels(channels, dsQuery)
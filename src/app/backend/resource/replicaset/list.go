@@ -49,7 +49,7 @@ func GetReplicaSetList(client kubernetes.Interface, nsQuery *common.NamespaceQue
 	glog.Info("Getting list of all replica sets in the cluster")
 
 	channels := &common.ResourceChannels{
-		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		PodList:        common.GetPodListChannel(client, nsQuery, 1),
 		EventList:      common.GetEventListChannel(client, nsQuery, 1),
 	}
@@ -82,6 +82,8 @@ func GetReplicaSetListFromChannels(channels *common.ResourceChannels,
 	}
 
 	rsList := ToReplicaSetList(replicaSets.Items, pods.Items, events.Items, nonCriticalErrors, dsQuery)
+
+	rsList.ListMeta.Continue = replicaSets.Continue
 	rsList.Status = getStatus(replicaSets, pods.Items, events.Items)
 	return rsList, nil
 }
@@ -43,7 +43,7 @@ func GetReplicationControllerList(client kubernetes.Interface, nsQuery *common.N
 	glog.Info("Getting list of all replication controllers in the cluster")
 
 	channels := &common.ResourceChannels{
-		ReplicationControllerList: common.GetReplicationControllerListChannel(client, nsQuery, 1),
+		ReplicationControllerList: common.GetReplicationControllerListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		PodList:                   common.GetPodListChannel(client, nsQuery, 1),
 		EventList:                 common.GetEventListChannel(client, nsQuery, 1),
 	}
@@ -76,6 +76,8 @@ func GetReplicationControllerListFromChannels(channels *common.ResourceChannels,
 	}
 
 	rcs := toReplicationControllerList(rcList.Items, dsQuery, podList.Items, eventList.Items, nonCriticalErrors)
+
+	rcs.ListMeta.Continue = rcList.Continue
 	rcs.Status = getStatus(rcList, podList.Items, eventList.Items)
 	return rcs, nil
 }
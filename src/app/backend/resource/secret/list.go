@@ -85,14 +85,16 @@ func GetSecretList(client kubernetes.Interface, namespace *common.NamespaceQuery
 	dsQuery *dataselect.DataSelectQuery) (*SecretList, error) {
 
 	glog.Infof("Getting list of secrets in %s namespace\n", namespace)
-	secretList, err := client.CoreV1().Secrets(namespace.ToRequestParam()).List(api.ListEverything)
+	secretList, err := client.CoreV1().Secrets(namespace.ToRequestParam()).List(dsQuery.ListOptions())
 
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil {
 		return nil, criticalError
 	}
 
-	return toSecretList(secretList.Items, nonCriticalErrors, dsQuery), nil
+	result := toSecretList(secretList.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = secretList.Continue
+	return result, nil
 }
 
 func GetSecretListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery) (
@@ -105,7 +107,9 @@ func GetSecretListFromChannels(channels *common.ResourceChannels, dsQuery *datas
   	return nil, criticalError
 	}
 
-  return toSecretList(secretList.Items, nonCriticalErrors, dsQuery), nil
+  result := toSecretList(secretList.Items, nonCriticalErrors, dsQuery)
+  result.ListMeta.Continue = secretList.Continue
+  return result, nil
 }
 
 func toSecret(secret *v1.Secret) *Secret {
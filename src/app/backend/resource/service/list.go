@@ -50,7 +50,7 @@ func GetServiceList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 	glog.Info("Getting list of all services in the cluster")
 
 	channels := &common.ResourceChannels{
-		ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
+		ServiceList: common.GetServiceListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 	}
 
 	return GetServiceListFromChannels(channels, dsQuery)
@@ -68,7 +68,9 @@ func GetServiceListFromChannels(channels *common.ResourceChannels,
 		return nil, criticalError
 	}
 
-	return ToServiceList(serviceList.Items, nonCriticalErrors, dsQuery), nil
+	result := ToServiceList(serviceList.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = serviceList.Continue
+	return result, nil
 }
 
 // ToService returns api service object based on kubernetes service object
@@ -49,7 +49,7 @@ func GetStatefulSetList(client kubernetes.Interface, nsQuery *common.NamespaceQu
 	glog.Info("Getting list of all pet sets in the cluster")
 
 	channels := &common.ResourceChannels{
-		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
 		PodList:         common.GetPodListChannel(client, nsQuery, 1),
 		EventList:       common.GetEventListChannel(client, nsQuery, 1),
 	}
@@ -84,6 +84,8 @@ func GetStatefulSetListFromChannels(channels *common.ResourceChannels, dsQuery *
 	}
 
 	ssList := toStatefulSetList(statefulSets.Items, pods.Items, events.Items, nonCriticalErrors, dsQuery)
+
+	ssList.ListMeta.Continue = statefulSets.Continue
 	ssList.Status = getStatus(statefulSets, pods.Items, events.Items)
 	return ssList, nil
 }
@@ -38,7 +38,7 @@ func GetStorageClassList(client kubernetes.Interface, dsQuery *dataselect.DataSe
 	glog.Info("Getting list of storage classes in the cluster")
 
 	channels := &common.ResourceChannels{
-		StorageClassList: common.GetStorageClassListChannel(client, 1),
+		StorageClassList: common.GetStorageClassListChannelWithOptions(client, dsQuery.ListOptions(), 1),
 	}
 
 	return GetStorageClassListFromChannels(channels, dsQuery)
@@ -55,7 +55,9 @@ func GetStorageClassListFromChannels(channels *common.ResourceChannels,
 		return nil, criticalError
 	}
 
-	return toStorageClassList(storageClassList.Items, nonCriticalErrors, dsQuery), nil
+	result := toStorageClassList(storageClassList.Items, nonCriticalErrors, dsQuery)
+	result.ListMeta.Continue = storageClassList.Continue
+	return result, nil
 }
 
 func toStorageClassList(storageClasses []storage.StorageClass,
@@ -31,28 +31,48 @@ type Workloads struct {
 	Errors []error `json:"errors"`
 }
 
+// GetWorkloads returns lists of all workloads. Selectors of the query are applied to listed workloads only. Pods,
+// replica sets and events used to compute status of workloads are listed without them.
 func GetWorkloads(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*Workloads, error) {
 
 	glog.Info("Getting list of all workloads")
 	channels := &common.ResourceChannels{
-		ReplicationControllerList: common.GetReplicationControllerListChannel(client, nsQuery, 1),
-		ReplicaSetList:            common.GetReplicaSetListChannel(client, nsQuery, 2),
-		JobList:                   common.GetJobListChannel(client, nsQuery, 1),
-		CronJobList:               common.GetCronJobListChannel(client, nsQuery, 1),
-		DeploymentList:            common.GetDeploymentListChannel(client, nsQuery, 1),
-		DaemonSetList:             common.GetDaemonSetListChannel(client, nsQuery, 1),
-		StatefulSetList:           common.GetStatefulSetListChannel(client, nsQuery, 1),
+		ReplicationControllerList: common.GetReplicationControllerListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("replicationcontroller"), 1),
+		ReplicaSetList:            common.GetReplicaSetListChannel(client, nsQuery, 1),
+		JobList:                   common.GetJobListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("job"), 1),
+		CronJobList:               common.GetCronJobListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("cronjob"), 1),
+		DeploymentList:            common.GetDeploymentListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("deployment"), 1),
+		DaemonSetList:             common.GetDaemonSetListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("daemonset"), 1),
+		StatefulSetList:           common.GetStatefulSetListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("statefulset"), 1),
 		ServiceList:               common.GetServiceListChannel(client, nsQuery, 1),
-		PodList:                   common.GetPodListChannel(client, nsQuery, 7),
+		PodList:                   common.GetPodListChannel(client, nsQuery, 6),
 		EventList:                 common.GetEventListChannel(client, nsQuery, 7),
 	}
 
-	return GetWorkloadsFromChannels(channels, dsQuery)
+	// Listed pods and replica sets are selected, unlike the ones used to compute status of other workloads.
+	listedPods := common.GetPodListChannelWithOptions(client, nsQuery, dsQuery.ListOptionsFor("pod"), 1)
+	listedReplicaSets := common.GetReplicaSetListChannelWithOptions(client, nsQuery,
+		dsQuery.ListOptionsFor("replicaset"), 1)
+
+	return getWorkloadsFromChannels(channels, listedPods, listedReplicaSets, dsQuery)
 }
 
+// GetWorkloadsFromChannels returns lists of all workloads from the channel sources.
 func GetWorkloadsFromChannels(channels *common.ResourceChannels,
 	dsQuery *dataselect.DataSelectQuery) (*Workloads, error) {
+	return getWorkloadsFromChannels(channels, channels.PodList, channels.ReplicaSetList, dsQuery)
+}
+
+// Returns lists of all workloads. Listed pods and replica sets are read from given channels, pods and replica
+// sets used to compute status of workloads are read from channels of other lists.
+func getWorkloadsFromChannels(channels *common.ResourceChannels, listedPods common.PodListChannel,
+	listedReplicaSets common.ReplicaSetListChannel, dsQuery *dataselect.DataSelectQuery) (*Workloads, error) {
+
+	podChannels := *channels
+	podChannels.PodList = listedPods
+	rsChannels := *channels
+	rsChannels.ReplicaSetList = listedReplicaSets
 
 	numErrs := 8
 	errChan := make(chan error, numErrs)
@@ -72,7 +92,7 @@ func GetWorkloadsFromChannels(channels *common.ResourceChannels,
 	}()
 
 	go func() {
-		items, err := rs.GetReplicaSetListFromChannels(channels, dsQuery)
+		items, err := rs.GetReplicaSetListFromChannels(&rsChannels, dsQuery)
 		errChan <- err
 		rsChan <- items
 	}()
@@ -96,7 +116,7 @@ func GetWorkloadsFromChannels(channels *common.ResourceChannels,
 	}()
 
 	go func() {
-		items, err := pod.GetPodListFromChannels(channels, dsQuery)
+		items, err := pod.GetPodListFromChannels(&podChannels, dsQuery)
 		errChan <- err
 		podChan <- items
 	}()